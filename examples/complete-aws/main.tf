@@ -217,7 +217,10 @@ resource "zenml_service_connector" "aws" {
   configuration = {
     region   = var.region
     role_arn = aws_iam_role.zenml.arn
-    aws_access_key_id = aws_iam_access_key.iam_user_access_key.id
+  }
+
+  secret_configuration = {
+    aws_access_key_id     = aws_iam_access_key.iam_user_access_key.id
     aws_secret_access_key = aws_iam_access_key.iam_user_access_key.secret
   }
 
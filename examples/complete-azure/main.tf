@@ -166,6 +166,9 @@ resource "zenml_service_connector" "azure" {
     storage_account = "${azurerm_storage_account.artifacts.name}"
     tenant_id = "${data.azurerm_client_config.current.tenant_id}"
     client_id = "${azuread_application.service_principal_app.client_id}"
+  }
+
+  secret_configuration = {
     client_secret = "${azuread_service_principal_password.service_principal_password.value}"
   }
 
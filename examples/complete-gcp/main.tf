@@ -104,8 +104,11 @@ resource "zenml_service_connector" "gcp" {
   auth_method = "service-account"
 
   configuration = {
-    project_id           = var.project_id
-    region               = var.region
+    project_id = var.project_id
+    region     = var.region
+  }
+
+  secret_configuration = {
     service_account_json = google_service_account_key.zenml_sa_key.private_key
   }
 
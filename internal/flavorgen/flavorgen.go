@@ -0,0 +1,371 @@
+// Package flavorgen generates the typed stack component resources of the
+// provider from the configuration schemas of ZenML flavors.
+package flavorgen
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/format"
+	"sort"
+	"strings"
+)
+
+// Flavor is a stack component flavor as returned by the ZenML flavors API,
+// reduced to the fields used by the generator.
+type Flavor struct {
+	ID       string          `json:"id"`
+	Name     string          `json:"name"`
+	Body     *FlavorBody     `json:"body,omitempty"`
+	Metadata *FlavorMetadata `json:"metadata,omitempty"`
+}
+
+type FlavorBody struct {
+	Type        string  `json:"type"`
+	Integration *string `json:"integration,omitempty"`
+}
+
+type FlavorMetadata struct {
+	ConfigSchema map[string]interface{} `json:"config_schema"`
+}
+
+// Component describes the typed resource generated for a flavor.
+type Component struct {
+	TypeName      string
+	ComponentType string
+	Flavor        string
+	Integration   string
+	Description   string
+	Attributes    []Attribute
+}
+
+// Attribute describes a typed configuration attribute of a component.
+type Attribute struct {
+	Name        string
+	Key         string
+	Kind        string
+	Description string
+	Required    bool
+	Sensitive   bool
+	// Default is the JSON-encoded default value, or empty if the attribute
+	// has no default or its default cannot be expressed in the schema.
+	Default string
+	// Enum lists the allowed values of string attributes.
+	Enum []string
+}
+
+// Attribute kinds, matching the flavorAttributeKind values of the provider.
+const (
+	KindString  = "string"
+	KindBool    = "bool"
+	KindInt64   = "int64"
+	KindFloat64 = "float64"
+	KindList    = "list"
+	KindMap     = "map"
+	KindJSON    = "json"
+)
+
+// reservedNames are attribute names that configuration keys cannot use,
+// either because the typed resources already define them or because they are
+// Terraform meta-arguments. Configuration keys with these names are exposed
+// with a config_ prefix.
+var reservedNames = map[string]bool{
+	"id":                          true,
+	"name":                        true,
+	"type":                        true,
+	"flavor":                      true,
+	"configuration":               true,
+	"connector_id":                true,
+	"connector_resource_id":       true,
+	"validate_connector_resource": true,
+	"labels":                      true,
+	"labels_all":                  true,
+	"labels_mode":                 true,
+	"created":                     true,
+	"updated":                     true,
+	"adopt_existing":              true,
+	"deletion_protection":         true,
+	"count":                       true,
+	"for_each":                    true,
+	"provider":                    true,
+	"depends_on":                  true,
+	"lifecycle":                   true,
+	"provisioner":                 true,
+	"connection":                  true,
+}
+
+// ParseSnapshot decodes a snapshot of flavors, which holds a JSON list of
+// flavors as returned by the ZenML API.
+func ParseSnapshot(data []byte) ([]Flavor, error) {
+	var flavors []Flavor
+	if err := json.Unmarshal(data, &flavors); err != nil {
+		return nil, fmt.Errorf("unable to decode flavor snapshot: %w", err)
+	}
+	return flavors, nil
+}
+
+// Components converts flavors to the typed resources generated for them,
+// sorted by resource type name.
+func Components(flavors []Flavor) ([]Component, error) {
+	components := make([]Component, 0, len(flavors))
+	seen := make(map[string]bool, len(flavors))
+
+	for _, flavor := range flavors {
+		if flavor.Body == nil {
+			return nil, fmt.Errorf("flavor %q has no type", flavor.Name)
+		}
+
+		c := Component{
+			TypeName:      flavor.Body.Type + "_" + identifier(flavor.Name),
+			ComponentType: flavor.Body.Type,
+			Flavor:        flavor.Name,
+		}
+		if flavor.Body.Integration != nil && *flavor.Body.Integration != "built-in" {
+			c.Integration = *flavor.Body.Integration
+		}
+		if seen[c.TypeName] {
+			return nil, fmt.Errorf("flavors %q map to the same resource type zenml_%s", flavor.Name, c.TypeName)
+		}
+		seen[c.TypeName] = true
+
+		var configSchema map[string]interface{}
+		if flavor.Metadata != nil {
+			configSchema = flavor.Metadata.ConfigSchema
+		}
+		c.Description = fmt.Sprintf(
+			"Manages a ZenML %s stack component of the `%s` flavor.",
+			strings.ReplaceAll(c.ComponentType, "_", " "), c.Flavor,
+		)
+
+		attributes, err := schemaAttributes(configSchema)
+		if err != nil {
+			return nil, fmt.Errorf("flavor %s/%s: %w", c.ComponentType, c.Flavor, err)
+		}
+		c.Attributes = attributes
+
+		components = append(components, c)
+	}
+
+	sort.Slice(components, func(i, j int) bool {
+		return components[i].TypeName < components[j].TypeName
+	})
+	return components, nil
+}
+
+// schemaAttributes converts the properties of a flavor configuration JSON
+// schema to attributes, sorted by name.
+func schemaAttributes(configSchema map[string]interface{}) ([]Attribute, error) {
+	properties, _ := configSchema["properties"].(map[string]interface{})
+
+	required := make(map[string]bool)
+	if list, ok := configSchema["required"].([]interface{}); ok {
+		for _, key := range list {
+			if s, ok := key.(string); ok {
+				required[s] = true
+			}
+		}
+	}
+
+	names := make(map[string]string, len(properties))
+	attributes := make([]Attribute, 0, len(properties))
+	for key, raw := range properties {
+		property, ok := raw.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("property %q is not an object", key)
+		}
+
+		name := identifier(key)
+		if reservedNames[name] {
+			name = "config_" + name
+		}
+		if other, ok := names[name]; ok {
+			return nil, fmt.Errorf("properties %q and %q map to the same attribute %q", other, key, name)
+		}
+		names[name] = key
+
+		attributes = append(attributes, propertyAttribute(name, key, property, required[key]))
+	}
+
+	sort.Slice(attributes, func(i, j int) bool {
+		return attributes[i].Name < attributes[j].Name
+	})
+	return attributes, nil
+}
+
+func propertyAttribute(name, key string, property map[string]interface{}, required bool) Attribute {
+	a := Attribute{
+		Name:        name,
+		Key:         key,
+		Kind:        propertyKind(property),
+		Description: propertyDescription(property),
+		Required:    required,
+		Sensitive:   propertySensitive(property),
+	}
+
+	if a.Kind == KindString {
+		a.Enum = propertyEnum(property)
+	}
+
+	if value, ok := property["default"]; ok && value != nil && !required {
+		switch a.Kind {
+		case KindString, KindBool, KindInt64, KindFloat64:
+			// Empty defaults only stand in for values the flavor computes.
+			if encoded, err := json.Marshal(value); err == nil && string(encoded) != `""` {
+				a.Default = string(encoded)
+			}
+		}
+	}
+
+	return a
+}
+
+// nonNullVariant returns the only variant of a property that also allows
+// null, which is how optional fields appear in pydantic schemas.
+func nonNullVariant(property map[string]interface{}) map[string]interface{} {
+	variants, ok := property["anyOf"].([]interface{})
+	if !ok {
+		return property
+	}
+
+	var variant map[string]interface{}
+	for _, v := range variants {
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return property
+		}
+		if m["type"] == "null" {
+			continue
+		}
+		if variant != nil {
+			// Unions of several types have no typed equivalent.
+			return property
+		}
+		variant = m
+	}
+	if variant == nil {
+		return property
+	}
+	return variant
+}
+
+func propertyKind(property map[string]interface{}) string {
+	property = nonNullVariant(property)
+
+	switch property["type"] {
+	case "string":
+		return KindString
+	case "boolean":
+		return KindBool
+	case "integer":
+		return KindInt64
+	case "number":
+		return KindFloat64
+	case "array":
+		if items, ok := property["items"].(map[string]interface{}); ok && items["type"] == "string" {
+			return KindList
+		}
+	case "object":
+		if values, ok := property["additionalProperties"].(map[string]interface{}); ok && values["type"] == "string" {
+			return KindMap
+		}
+	}
+	if _, ok := property["enum"]; ok {
+		return KindString
+	}
+	return KindJSON
+}
+
+func propertyEnum(property map[string]interface{}) []string {
+	values, ok := nonNullVariant(property)["enum"].([]interface{})
+	if !ok {
+		return nil
+	}
+
+	enum := make([]string, 0, len(values))
+	for _, v := range values {
+		s, ok := v.(string)
+		if !ok {
+			return nil
+		}
+		enum = append(enum, s)
+	}
+	return enum
+}
+
+// SensitiveKeys returns the configuration keys of a flavor configuration JSON
+// schema that hold secrets.
+func SensitiveKeys(configSchema map[string]interface{}) map[string]bool {
+	properties, _ := configSchema["properties"].(map[string]interface{})
+
+	keys := make(map[string]bool)
+	for key, raw := range properties {
+		if property, ok := raw.(map[string]interface{}); ok && propertySensitive(property) {
+			keys[key] = true
+		}
+	}
+	return keys
+}
+
+// propertySensitive reports whether a property holds a secret. ZenML marks
+// secret fields with "sensitive", and pydantic renders SecretStr fields with
+// the password format.
+func propertySensitive(property map[string]interface{}) bool {
+	if sensitive, ok := property["sensitive"].(bool); ok && sensitive {
+		return true
+	}
+	return nonNullVariant(property)["format"] == "password"
+}
+
+func propertyDescription(property map[string]interface{}) string {
+	description, _ := property["description"].(string)
+	if description == "" {
+		description, _ = property["title"].(string)
+	}
+	return strings.Join(strings.Fields(description), " ")
+}
+
+// identifier converts a flavor or configuration key name to a Terraform
+// identifier.
+func identifier(name string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(name) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') || r == '_' {
+			b.WriteRune(r)
+		} else {
+			b.WriteRune('_')
+		}
+	}
+	return b.String()
+}
+
+// GenerateGo renders the Go source declaring the flavor component specs of the
+// provider.
+func GenerateGo(components []Component, source string) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := goTemplate.Execute(&buf, struct {
+		Source     string
+		Components []Component
+	}{source, components}); err != nil {
+		return nil, err
+	}
+
+	formatted, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("unable to format generated code: %w", err)
+	}
+	return formatted, nil
+}
+
+// GenerateDocs renders the documentation page of each component, keyed by
+// file name.
+func GenerateDocs(components []Component) (map[string][]byte, error) {
+	docs := make(map[string][]byte, len(components))
+	for _, c := range components {
+		var buf bytes.Buffer
+		if err := docTemplate.Execute(&buf, c); err != nil {
+			return nil, fmt.Errorf("unable to render documentation of zenml_%s: %w", c.TypeName, err)
+		}
+		docs[c.TypeName+".md"] = buf.Bytes()
+	}
+	return docs, nil
+}
@@ -0,0 +1,154 @@
+package flavorgen
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func testComponents(t *testing.T) map[string]Component {
+	t.Helper()
+
+	data, err := os.ReadFile(filepath.Join("..", "provider", "flavor_schemas.json"))
+	if err != nil {
+		t.Fatalf("unable to read snapshot: %s", err)
+	}
+	flavors, err := ParseSnapshot(data)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	components, err := Components(flavors)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	byName := make(map[string]Component, len(components))
+	for _, c := range components {
+		byName[c.TypeName] = c
+	}
+	return byName
+}
+
+func attribute(t *testing.T, c Component, name string) Attribute {
+	t.Helper()
+	for _, a := range c.Attributes {
+		if a.Name == name {
+			return a
+		}
+	}
+	t.Fatalf("zenml_%s has no attribute %q", c.TypeName, name)
+	return Attribute{}
+}
+
+func TestComponents(t *testing.T) {
+	components := testComponents(t)
+
+	for _, name := range []string{"orchestrator_kubernetes", "artifact_store_s3", "container_registry_gcp"} {
+		if _, ok := components[name]; !ok {
+			t.Errorf("expected a zenml_%s resource", name)
+		}
+	}
+
+	s3 := components["artifact_store_s3"]
+	if a := attribute(t, s3, "path"); !a.Required || a.Kind != KindString {
+		t.Errorf("expected path to be a required string, got %+v", a)
+	}
+	if a := attribute(t, s3, "secret"); !a.Sensitive || a.Kind != KindString || a.Required {
+		t.Errorf("expected secret to be an optional sensitive string, got %+v", a)
+	}
+	if a := attribute(t, s3, "client_kwargs"); a.Kind != KindJSON {
+		t.Errorf("expected client_kwargs to be JSON, got %+v", a)
+	}
+
+	kubernetes := components["orchestrator_kubernetes"]
+	if kubernetes.Integration != "kubernetes" {
+		t.Errorf("expected the kubernetes integration, got %q", kubernetes.Integration)
+	}
+	if a := attribute(t, kubernetes, "synchronous"); a.Kind != KindBool || a.Default != "true" {
+		t.Errorf("expected synchronous to be a bool defaulting to true, got %+v", a)
+	}
+	if a := attribute(t, kubernetes, "max_parallelism"); a.Kind != KindInt64 || a.Default != "" {
+		t.Errorf("expected max_parallelism to be an int64 without default, got %+v", a)
+	}
+	if a := attribute(t, kubernetes, "kubernetes_namespace"); a.Default != `"zenml"` {
+		t.Errorf("expected kubernetes_namespace to default to zenml, got %+v", a)
+	}
+	if a := attribute(t, kubernetes, "image_pull_policy"); len(a.Enum) != 3 {
+		t.Errorf("expected image_pull_policy to list its values, got %+v", a)
+	}
+
+	if local := components["orchestrator_local"]; local.Integration != "" || len(local.Attributes) != 0 {
+		t.Errorf("expected the local orchestrator to be built in and have no attributes, got %+v", local)
+	}
+}
+
+func TestSchemaAttributes_Kinds(t *testing.T) {
+	attributes, err := schemaAttributes(map[string]interface{}{
+		"properties": map[string]interface{}{
+			"zones": map[string]interface{}{
+				"type":  "array",
+				"items": map[string]interface{}{"type": "string"},
+			},
+			"selectors": map[string]interface{}{
+				"type":                 "object",
+				"additionalProperties": map[string]interface{}{"type": "string"},
+			},
+			"ratio": map[string]interface{}{"type": "number", "default": 0.5},
+			"either": map[string]interface{}{
+				"anyOf": []interface{}{
+					map[string]interface{}{"type": "string"},
+					map[string]interface{}{"type": "integer"},
+				},
+			},
+			"count": map[string]interface{}{"type": "integer"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	want := map[string]string{
+		"config_count": KindInt64,
+		"either":       KindJSON,
+		"ratio":        KindFloat64,
+		"selectors":    KindMap,
+		"zones":        KindList,
+	}
+	if len(attributes) != len(want) {
+		t.Fatalf("expected %d attributes, got %+v", len(want), attributes)
+	}
+	for _, a := range attributes {
+		if want[a.Name] != a.Kind {
+			t.Errorf("expected %s to be %s, got %s", a.Name, want[a.Name], a.Kind)
+		}
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	components := testComponents(t)
+	list := []Component{components["artifact_store_s3"], components["orchestrator_local"]}
+
+	code, err := GenerateGo(list, "flavor_schemas.json")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if !strings.Contains(string(code), "DO NOT EDIT") || !strings.Contains(string(code), `TypeName:      "artifact_store_s3"`) {
+		t.Errorf("unexpected generated code:\n%s", code)
+	}
+
+	docs, err := GenerateDocs(list)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	page := string(docs["artifact_store_s3.md"])
+	for _, want := range []string{
+		"# zenml_artifact_store_s3 (Resource)",
+		"* `secret` - (Optional, Sensitive)",
+		"to   = zenml_artifact_store_s3.example",
+	} {
+		if !strings.Contains(page, want) {
+			t.Errorf("expected documentation to contain %q, got:\n%s", want, page)
+		}
+	}
+}
@@ -0,0 +1,259 @@
+package flavorgen
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+)
+
+var templateFuncs = template.FuncMap{
+	"quote":        func(s string) string { return fmt.Sprintf("%q", s) },
+	"kindConstant": kindConstant,
+	"example":      exampleValue,
+	"argument":     argumentDescription,
+	"exampleWidth": exampleWidth,
+	"padding":      padding,
+	"words":        func(s string) string { return strings.ReplaceAll(s, "_", " ") },
+	"dashes":       func(s string) string { return strings.ReplaceAll(s, "_", "-") },
+}
+
+// kindConstant returns the name of the provider constant of an attribute kind.
+func kindConstant(kind string) string {
+	switch kind {
+	case KindString:
+		return "flavorAttributeString"
+	case KindBool:
+		return "flavorAttributeBool"
+	case KindInt64:
+		return "flavorAttributeInt64"
+	case KindFloat64:
+		return "flavorAttributeFloat64"
+	case KindList:
+		return "flavorAttributeList"
+	case KindMap:
+		return "flavorAttributeMap"
+	default:
+		return "flavorAttributeJSON"
+	}
+}
+
+// exampleValue returns an HCL placeholder for an attribute in the example
+// usage of a component.
+func exampleValue(a Attribute) string {
+	switch a.Kind {
+	case KindBool:
+		return "true"
+	case KindInt64, KindFloat64:
+		return "1"
+	case KindList:
+		return `["value"]`
+	case KindMap:
+		return `{ key = "value" }`
+	case KindJSON:
+		return "jsonencode({})"
+	}
+	if len(a.Enum) > 0 {
+		return fmt.Sprintf("%q", a.Enum[0])
+	}
+	if a.Key == "path" || a.Key == "uri" {
+		return fmt.Sprintf("%q", "<"+strings.ReplaceAll(a.Key, "_", "-")+">")
+	}
+	return fmt.Sprintf("%q", "my-"+strings.ReplaceAll(a.Key, "_", "-"))
+}
+
+// argumentDescription renders the argument reference entry of an attribute.
+func argumentDescription(a Attribute) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "* `%s` - ", a.Name)
+
+	qualifiers := []string{"Optional"}
+	if a.Required {
+		qualifiers[0] = "Required"
+	}
+	if a.Sensitive {
+		qualifiers = append(qualifiers, "Sensitive")
+	}
+	fmt.Fprintf(&b, "(%s)", strings.Join(qualifiers, ", "))
+
+	if a.Description != "" {
+		description := a.Description
+		if !strings.HasSuffix(description, ".") {
+			description += "."
+		}
+		b.WriteString(" " + description)
+	}
+	if a.Name != a.Key {
+		fmt.Fprintf(&b, " Sets the `%s` configuration key.", a.Key)
+	}
+	if a.Kind == KindJSON {
+		b.WriteString(" A JSON-encoded value, for example produced with `jsonencode()`.")
+	}
+	if len(a.Enum) > 0 {
+		values := make([]string, len(a.Enum))
+		for i, v := range a.Enum {
+			values[i] = "`" + v + "`"
+		}
+		fmt.Fprintf(&b, " Must be one of %s.", strings.Join(values, ", "))
+	}
+	if a.Default != "" {
+		fmt.Fprintf(&b, " Defaults to `%s`.", strings.Trim(a.Default, `"`))
+	}
+	return b.String()
+}
+
+// exampleWidth returns the length of the longest attribute name in the
+// example usage of a component.
+func exampleWidth(c Component) int {
+	width := len("name")
+	for _, a := range c.Attributes {
+		if a.Required && len(a.Name) > width {
+			width = len(a.Name)
+		}
+	}
+	return width
+}
+
+// padding returns the spaces that align the value of an attribute in an HCL
+// block whose longest attribute name has the given length.
+func padding(name string, width int) string {
+	if len(name) >= width {
+		return ""
+	}
+	return strings.Repeat(" ", width-len(name))
+}
+
+var goTemplate = template.Must(template.New("go").Funcs(templateFuncs).Parse(`// Code generated by tools/flavorgen from {{ .Source }}. DO NOT EDIT.
+
+package provider
+
+var flavorComponentSpecs = []flavorComponentSpec{
+{{- range .Components }}
+	{
+		TypeName:      {{ quote .TypeName }},
+		ComponentType: {{ quote .ComponentType }},
+		Flavor:        {{ quote .Flavor }},
+		Description:   {{ quote .Description }},
+		Attributes: []flavorAttributeSpec{
+{{- range .Attributes }}
+			{
+				Name: {{ quote .Name }},
+				Key:  {{ quote .Key }},
+				Kind: {{ kindConstant .Kind }},
+{{- if .Description }}
+				Description: {{ quote .Description }},
+{{- end }}
+{{- if .Required }}
+				Required: true,
+{{- end }}
+{{- if .Sensitive }}
+				Sensitive: true,
+{{- end }}
+{{- if .Default }}
+				Default: {{ quote .Default }},
+{{- end }}
+{{- if .Enum }}
+				Enum: []string{ {{- range $i, $v := .Enum }}{{ if $i }}, {{ end }}{{ quote $v }}{{ end -}} },
+{{- end }}
+			},
+{{- end }}
+		},
+	},
+{{- end }}
+}
+`))
+
+var docTemplate = template.Must(template.New("doc").Funcs(templateFuncs).Parse(`---
+page_title: "zenml_{{ .TypeName }} Resource - terraform-provider-zenml"
+subcategory: ""
+description: |-
+  {{ .Description }}
+---
+
+# zenml_{{ .TypeName }} (Resource)
+
+{{ .Description }} It manages the same objects as ` + "`zenml_stack_component`" + `, with typed attributes for the configuration of the flavor.
+{{- if .Integration }}
+
+-> **Note** Pipelines running on stacks with this component need the ZenML ` + "`{{ .Integration }}`" + ` integration, installed with ` + "`zenml integration install {{ .Integration }}`" + `.
+{{- end }}
+
+This page is generated from the flavor configuration schema by ` + "`tools/flavorgen`" + `.
+
+## Example Usage
+
+` + "```hcl" + `
+resource "zenml_{{ .TypeName }}" "example" {
+{{- $width := exampleWidth . }}
+  name{{ padding "name" $width }} = "my-{{ .Flavor }}-{{ dashes .ComponentType }}"
+{{- range .Attributes }}{{ if .Required }}
+  {{ .Name }}{{ padding .Name $width }} = {{ example . }}
+{{- end }}{{ end }}
+
+  labels = {
+    environment = "production"
+  }
+}
+` + "```" + `
+
+## Argument Reference
+
+* ` + "`name`" + ` - (Required) The name of the component.
+* ` + "`connector_id`" + ` - (Optional) The ID of the service connector to use with this component.
+* ` + "`connector_resource_id`" + ` - (Optional) The ID of the connector resource to use with this component. Requires ` + "`connector_id`" + `.
+* ` + "`validate_connector_resource`" + ` - (Optional) Whether to check during plan that the service connector can access ` + "`connector_resource_id`" + `. When it cannot, the plan fails and lists the resources the connector can access.
+* ` + "`labels`" + ` - (Optional) A map of labels to associate with the component.
+* ` + "`labels_mode`" + ` - (Optional) Either ` + "`authoritative`" + ` (default), which removes labels that are not configured, or ` + "`additive`" + `, which only manages the configured keys and keeps the labels that others add to the component.
+* ` + "`adopt_existing`" + ` - (Optional) Whether to adopt an existing component with the same name and type when creating the component fails because the name is taken. The existing component must have the same flavor. Defaults to the ` + "`adopt_existing`" + ` setting of the provider.
+* ` + "`deletion_protection`" + ` - (Optional) Whether Terraform is prevented from deleting or replacing the component. Defaults to ` + "`false`" + `.
+{{- if .Attributes }}
+
+### Configuration
+
+Optional configuration attributes without a default that are left unset take their value from the ZenML server, which is shown in ` + "`effective_configuration`" + `. Removing such an attribute from the configuration unsets it again.
+
+{{ range .Attributes }}{{ argument . }}
+{{ end }}
+{{- else }}
+
+The ` + "`{{ .Flavor }}`" + ` flavor has no configuration attributes.
+{{ end }}
+## Attributes Reference
+
+In addition to all arguments above, the following attributes are exported:
+
+* ` + "`id`" + ` - The ID of the stack component.
+* ` + "`labels_all`" + ` - All labels of the component, including the provider's ` + "`default_labels`" + ` and managed-by labels.
+* ` + "`effective_configuration`" + ` - (Sensitive) The complete configuration of the component on the server, including the defaults of the flavor. Configuration attributes are only checked for drift when they are set.
+* ` + "`created`" + ` - The timestamp when the stack component was created.
+* ` + "`updated`" + ` - The timestamp when the stack component was last updated.
+
+## Import
+
+Components can be imported using the ` + "`id`" + ` or the ` + "`name`" + `, e.g.
+
+` + "```shell" + `
+$ terraform import zenml_{{ .TypeName }}.example 12345678-1234-1234-1234-123456789012
+` + "```" + `
+
+With Terraform 1.12 or later, components can also be imported by resource identity:
+
+` + "```hcl" + `
+import {
+  to = zenml_{{ .TypeName }}.example
+  identity = {
+    id = "12345678-1234-1234-1234-123456789012"
+  }
+}
+` + "```" + `
+
+## Moving from zenml_stack_component
+
+A ` + "`zenml_stack_component`" + ` of the ` + "`{{ .Flavor }}`" + ` {{ words .ComponentType }} flavor can be moved to this resource without recreating the component. Replace the resource in the configuration, converting the entries of ` + "`configuration`" + ` to attributes, and add a ` + "`moved`" + ` block (requires Terraform 1.8 or later):
+
+` + "```hcl" + `
+moved {
+  from = zenml_stack_component.example
+  to   = zenml_{{ .TypeName }}.example
+}
+` + "```" + `
+`))
@@ -0,0 +1,63 @@
+// adopt.go
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// adoptExistingAttribute returns the schema attribute that lets a resource
+// take over an existing object with the same name instead of failing to
+// create it.
+func adoptExistingAttribute() schema.BoolAttribute {
+	return schema.BoolAttribute{
+		MarkdownDescription: "Whether to adopt an existing object with the same " +
+			"name, such as one left behind by a failed run, when creating " +
+			"the resource fails because the name is taken. The object is " +
+			"updated to match the configuration and managed by Terraform " +
+			"from then on. Defaults to the `adopt_existing` setting of the " +
+			"provider.",
+		Optional: true,
+	}
+}
+
+// shouldAdoptExisting returns whether a resource adopts an existing object on
+// a name conflict. The resource setting takes precedence over the provider's.
+func shouldAdoptExisting(client *Client, value types.Bool) bool {
+	if !value.IsNull() && !value.IsUnknown() {
+		return value.ValueBool()
+	}
+	return client.AdoptExisting
+}
+
+// findExistingID returns the ID of the object that a create conflicted with,
+// looked up with the given list filter.
+func findExistingID[T any](
+	ctx context.Context,
+	kind, name string,
+	filter map[string]string,
+	list func(context.Context, *ListParams) (*Page[T], error),
+	idOf func(T) string,
+) (string, error) {
+	items, err := collectPages(ctx, &ListParams{Filter: filter}, list)
+	if err != nil {
+		return "", err
+	}
+
+	switch len(items) {
+	case 0:
+		return "", fmt.Errorf("the server reported that %s %q already exists, but it could not be found", kind, name)
+	case 1:
+		return idOf(items[0]), nil
+	}
+
+	ids := make([]string, len(items))
+	for i, item := range items {
+		ids[i] = idOf(item)
+	}
+	return "", fmt.Errorf("%d %ss named %q exist: %s", len(items), kind, name, strings.Join(ids, ", "))
+}
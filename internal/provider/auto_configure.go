@@ -0,0 +1,510 @@
+// auto_configure.go
+package provider
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+	"gopkg.in/yaml.v3"
+)
+
+// autoConfigureOptions selects the local credentials that auto_configure
+// reads. Empty options fall back to the defaults of the respective tools.
+type autoConfigureOptions struct {
+	AWSProfile         string
+	GCPCredentialsFile string
+	KubernetesContext  string
+	DockerRegistry     string
+}
+
+// autoConfiguration is the connector configuration built from local
+// credentials, split into plain and secret values.
+type autoConfiguration struct {
+	Configuration map[string]string
+	Secrets       map[string]string
+}
+
+// Fingerprint returns a digest of the configuration, which is kept in state
+// to detect changes of the local credentials without storing them. The digest
+// is salted with the identity of the connector and slow to compute, so that
+// weak passwords cannot be recovered from state by guessing.
+func (c *autoConfiguration) Fingerprint(connectorType, authMethod, name string) string {
+	salt := sha256.Sum256([]byte(strings.Join([]string{"zenml-connector", connectorType, authMethod, name}, "\x00")))
+	encoded, _ := json.Marshal([]map[string]string{c.Configuration, c.Secrets})
+	key := argon2.IDKey(encoded, salt[:], 2, 19*1024, 1, 32)
+	return base64.RawStdEncoding.EncodeToString(key)
+}
+
+// autoConfigureMethod lists the configuration keys of an authentication
+// method that auto_configure can fill in.
+type autoConfigureMethod struct {
+	required []string
+	optional []string
+}
+
+var (
+	kubernetesServerKeys = []string{"cluster_name", "certificate_authority", "insecure"}
+
+	autoConfigureMethods = map[string]map[string]autoConfigureMethod{
+		"aws": {
+			"secret-key": {required: []string{"region", "aws_access_key_id", "aws_secret_access_key"}},
+			"sts-token":  {required: []string{"region", "aws_access_key_id", "aws_secret_access_key", "aws_session_token"}},
+		},
+		"gcp": {
+			"service-account":  {required: []string{"project_id", "service_account_json"}},
+			"external-account": {required: []string{"project_id", "external_account_json"}},
+			"user-account":     {required: []string{"project_id", "user_account_json"}},
+		},
+		"azure": {
+			"service-principal": {
+				required: []string{"tenant_id", "client_id", "client_secret"},
+				optional: []string{"subscription_id"},
+			},
+		},
+		"kubernetes": {
+			"token":    {required: []string{"server", "token"}, optional: kubernetesServerKeys},
+			"password": {required: []string{"server", "username", "password"}, optional: kubernetesServerKeys},
+		},
+		"docker": {
+			"password": {required: []string{"username", "password"}, optional: []string{"registry"}},
+		},
+	}
+
+	// connectorSecretKeys are the connector configuration keys that ZenML
+	// stores as secrets. The server never returns them in the configuration.
+	connectorSecretKeys = map[string]bool{
+		"aws_access_key_id":     true,
+		"aws_secret_access_key": true,
+		"aws_session_token":     true,
+		"service_account_json":  true,
+		"external_account_json": true,
+		"user_account_json":     true,
+		"client_secret":         true,
+		"certificate_authority": true,
+		"token":                 true,
+		"password":              true,
+	}
+)
+
+// autoConfigure builds the configuration of a connector of the given type and
+// authentication method from the local credentials of the matching tool, and
+// checks that it holds every key the authentication method requires.
+func autoConfigure(connectorType, authMethod string, opts autoConfigureOptions) (*autoConfiguration, error) {
+	method, ok := autoConfigureMethods[connectorType][authMethod]
+	if !ok {
+		supported := make([]string, 0, len(autoConfigureMethods[connectorType]))
+		for m := range autoConfigureMethods[connectorType] {
+			supported = append(supported, m)
+		}
+		if len(supported) == 0 {
+			return nil, fmt.Errorf("%s connectors cannot be auto-configured", connectorType)
+		}
+		sort.Strings(supported)
+		return nil, fmt.Errorf(
+			"the %s authentication method of %s connectors cannot be auto-configured, supported methods are: %s",
+			authMethod, connectorType, strings.Join(supported, ", "),
+		)
+	}
+
+	var values map[string]string
+	var source string
+	var err error
+	switch connectorType {
+	case "aws":
+		values, source, err = awsLocalCredentials(opts.AWSProfile)
+	case "gcp":
+		values, source, err = gcpLocalCredentials(opts.GCPCredentialsFile)
+	case "azure":
+		values, source = azureLocalCredentials()
+	case "kubernetes":
+		values, source, err = kubernetesLocalCredentials(opts.KubernetesContext)
+	case "docker":
+		values, source, err = dockerLocalCredentials(opts.DockerRegistry)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	missing := make([]string, 0)
+	for _, k := range method.required {
+		if values[k] == "" {
+			missing = append(missing, k)
+		}
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf(
+			"the %s authentication method of %s connectors requires %s, which %s does not provide",
+			authMethod, connectorType, strings.Join(missing, ", "), source,
+		)
+	}
+
+	c := &autoConfiguration{Configuration: map[string]string{}, Secrets: map[string]string{}}
+	for _, k := range append(method.required, method.optional...) {
+		v, ok := values[k]
+		switch {
+		case !ok || v == "":
+		case connectorSecretKeys[k]:
+			c.Secrets[k] = v
+		default:
+			c.Configuration[k] = v
+		}
+	}
+	return c, nil
+}
+
+// homePath returns a path relative to the home directory of the user.
+func homePath(elem ...string) string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(append([]string{home}, elem...)...)
+}
+
+// envOrHomePath returns the path set in an environment variable, or the given
+// path relative to the home directory.
+func envOrHomePath(name string, elem ...string) string {
+	if p := os.Getenv(name); p != "" {
+		return p
+	}
+	return homePath(elem...)
+}
+
+// awsLocalCredentials reads AWS credentials from the AWS_* environment
+// variables or, when a profile is selected or the variables are not set, from
+// a profile of the shared credentials file.
+func awsLocalCredentials(profile string) (map[string]string, string, error) {
+	values := map[string]string{}
+	var source string
+
+	if profile == "" && os.Getenv("AWS_ACCESS_KEY_ID") != "" {
+		source = "the AWS_* environment variables"
+		values["aws_access_key_id"] = os.Getenv("AWS_ACCESS_KEY_ID")
+		values["aws_secret_access_key"] = os.Getenv("AWS_SECRET_ACCESS_KEY")
+		values["aws_session_token"] = os.Getenv("AWS_SESSION_TOKEN")
+	} else {
+		if profile == "" {
+			profile = os.Getenv("AWS_PROFILE")
+		}
+		if profile == "" {
+			profile = "default"
+		}
+
+		credentialsFile := envOrHomePath("AWS_SHARED_CREDENTIALS_FILE", ".aws", "credentials")
+		source = fmt.Sprintf("the %q profile of %s", profile, credentialsFile)
+		credentials, err := readINIFile(credentialsFile)
+		if err != nil {
+			return nil, "", fmt.Errorf("unable to read AWS credentials: %w", err)
+		}
+		section, ok := credentials[profile]
+		if !ok {
+			return nil, "", fmt.Errorf("the AWS credentials file %s has no %q profile", credentialsFile, profile)
+		}
+		values["aws_access_key_id"] = section["aws_access_key_id"]
+		values["aws_secret_access_key"] = section["aws_secret_access_key"]
+		values["aws_session_token"] = section["aws_session_token"]
+		values["region"] = section["region"]
+
+		// The config file names profiles other than the default one
+		// "profile <name>".
+		configSection := profile
+		if profile != "default" {
+			configSection = "profile " + profile
+		}
+		if config, err := readINIFile(envOrHomePath("AWS_CONFIG_FILE", ".aws", "config")); err == nil {
+			if region := config[configSection]["region"]; region != "" {
+				values["region"] = region
+			}
+		}
+	}
+
+	for _, name := range []string{"AWS_REGION", "AWS_DEFAULT_REGION"} {
+		if region := os.Getenv(name); region != "" {
+			values["region"] = region
+			break
+		}
+	}
+	return values, source, nil
+}
+
+// readINIFile parses the INI files used by the AWS CLI into sections of
+// key-value pairs.
+func readINIFile(name string) (map[string]map[string]string, error) {
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+
+	sections := map[string]map[string]string{}
+	var current map[string]string
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case line == "", strings.HasPrefix(line, "#"), strings.HasPrefix(line, ";"):
+		case strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]"):
+			name := strings.TrimSpace(line[1 : len(line)-1])
+			if sections[name] == nil {
+				sections[name] = map[string]string{}
+			}
+			current = sections[name]
+		default:
+			k, v, ok := strings.Cut(line, "=")
+			if ok && current != nil {
+				current[strings.TrimSpace(k)] = strings.TrimSpace(v)
+			}
+		}
+	}
+	return sections, scanner.Err()
+}
+
+// gcpLocalCredentials reads a GCP credentials file, by default the one that
+// GOOGLE_APPLICATION_CREDENTIALS points to. The configuration key of the file
+// depends on the kind of credentials it holds.
+func gcpLocalCredentials(file string) (map[string]string, string, error) {
+	if file == "" {
+		file = os.Getenv("GOOGLE_APPLICATION_CREDENTIALS")
+	}
+	if file == "" {
+		return nil, "", fmt.Errorf("no GCP credentials file is selected, set gcp_credentials_file or GOOGLE_APPLICATION_CREDENTIALS")
+	}
+
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, "", fmt.Errorf("unable to read GCP credentials: %w", err)
+	}
+	var credentials struct {
+		Type           string `json:"type"`
+		ProjectID      string `json:"project_id"`
+		QuotaProjectID string `json:"quota_project_id"`
+	}
+	if err := json.Unmarshal(data, &credentials); err != nil {
+		return nil, "", fmt.Errorf("unable to decode GCP credentials file %s: %w", file, err)
+	}
+
+	values := map[string]string{}
+	switch credentials.Type {
+	case "service_account":
+		values["service_account_json"] = string(data)
+	case "external_account":
+		values["external_account_json"] = string(data)
+	case "authorized_user":
+		values["user_account_json"] = string(data)
+	}
+
+	values["project_id"] = credentials.ProjectID
+	if values["project_id"] == "" {
+		values["project_id"] = os.Getenv("GOOGLE_CLOUD_PROJECT")
+	}
+	if values["project_id"] == "" {
+		values["project_id"] = credentials.QuotaProjectID
+	}
+	return values, fmt.Sprintf("the %s credentials file %s", credentials.Type, file), nil
+}
+
+// azureLocalCredentials reads the service principal of the AZURE_*
+// environment variables.
+func azureLocalCredentials() (map[string]string, string) {
+	return map[string]string{
+		"tenant_id":       os.Getenv("AZURE_TENANT_ID"),
+		"client_id":       os.Getenv("AZURE_CLIENT_ID"),
+		"client_secret":   os.Getenv("AZURE_CLIENT_SECRET"),
+		"subscription_id": os.Getenv("AZURE_SUBSCRIPTION_ID"),
+	}, "the AZURE_* environment variables"
+}
+
+// kubeconfig is the part of a kubeconfig file that auto_configure reads.
+type kubeconfig struct {
+	CurrentContext string `yaml:"current-context"`
+	Contexts       []struct {
+		Name    string `yaml:"name"`
+		Context struct {
+			Cluster string `yaml:"cluster"`
+			User    string `yaml:"user"`
+		} `yaml:"context"`
+	} `yaml:"contexts"`
+	Clusters []struct {
+		Name    string `yaml:"name"`
+		Cluster struct {
+			Server                   string `yaml:"server"`
+			CertificateAuthority     string `yaml:"certificate-authority"`
+			CertificateAuthorityData string `yaml:"certificate-authority-data"`
+			InsecureSkipTLSVerify    bool   `yaml:"insecure-skip-tls-verify"`
+		} `yaml:"cluster"`
+	} `yaml:"clusters"`
+	Users []struct {
+		Name string `yaml:"name"`
+		User struct {
+			Token     string `yaml:"token"`
+			TokenFile string `yaml:"tokenFile"`
+			Username  string `yaml:"username"`
+			Password  string `yaml:"password"`
+		} `yaml:"user"`
+	} `yaml:"users"`
+}
+
+// kubernetesLocalCredentials reads the cluster and user of a kubeconfig
+// context, by default the current context. Like kubectl, it merges the files
+// listed in KUBECONFIG, the first file defining an entry taking precedence.
+func kubernetesLocalCredentials(contextName string) (map[string]string, string, error) {
+	files := filepath.SplitList(os.Getenv("KUBECONFIG"))
+	if len(files) == 0 {
+		files = []string{homePath(".kube", "config")}
+	}
+
+	var merged kubeconfig
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			if os.IsNotExist(err) && len(files) > 1 {
+				continue
+			}
+			return nil, "", fmt.Errorf("unable to read kubeconfig: %w", err)
+		}
+		var config kubeconfig
+		if err := yaml.Unmarshal(data, &config); err != nil {
+			return nil, "", fmt.Errorf("unable to decode kubeconfig %s: %w", file, err)
+		}
+		if merged.CurrentContext == "" {
+			merged.CurrentContext = config.CurrentContext
+		}
+		merged.Contexts = append(merged.Contexts, config.Contexts...)
+		merged.Clusters = append(merged.Clusters, config.Clusters...)
+		merged.Users = append(merged.Users, config.Users...)
+	}
+
+	if contextName == "" {
+		contextName = merged.CurrentContext
+	}
+	if contextName == "" {
+		return nil, "", fmt.Errorf("the kubeconfig has no current context, set kubernetes_context")
+	}
+
+	values := map[string]string{}
+	source := fmt.Sprintf("the %q kubeconfig context", contextName)
+	for _, c := range merged.Contexts {
+		if c.Name != contextName {
+			continue
+		}
+		for _, cluster := range merged.Clusters {
+			if cluster.Name != c.Context.Cluster {
+				continue
+			}
+			values["cluster_name"] = cluster.Name
+			values["server"] = cluster.Cluster.Server
+			if cluster.Cluster.InsecureSkipTLSVerify {
+				values["insecure"] = "true"
+			}
+			switch {
+			case cluster.Cluster.CertificateAuthorityData != "":
+				ca, err := base64.StdEncoding.DecodeString(cluster.Cluster.CertificateAuthorityData)
+				if err != nil {
+					return nil, "", fmt.Errorf("unable to decode the certificate authority of cluster %q: %w", cluster.Name, err)
+				}
+				values["certificate_authority"] = string(ca)
+			case cluster.Cluster.CertificateAuthority != "":
+				ca, err := os.ReadFile(cluster.Cluster.CertificateAuthority)
+				if err != nil {
+					return nil, "", fmt.Errorf("unable to read the certificate authority of cluster %q: %w", cluster.Name, err)
+				}
+				values["certificate_authority"] = string(ca)
+			}
+			break
+		}
+		for _, user := range merged.Users {
+			if user.Name != c.Context.User {
+				continue
+			}
+			values["token"] = user.User.Token
+			if values["token"] == "" && user.User.TokenFile != "" {
+				token, err := os.ReadFile(user.User.TokenFile)
+				if err != nil {
+					return nil, "", fmt.Errorf("unable to read the token of user %q: %w", user.Name, err)
+				}
+				values["token"] = strings.TrimSpace(string(token))
+			}
+			values["username"] = user.User.Username
+			values["password"] = user.User.Password
+			break
+		}
+		return values, source, nil
+	}
+	return nil, "", fmt.Errorf("the kubeconfig has no context %q", contextName)
+}
+
+// dockerHubRegistry is the key of Docker Hub in docker config files, which is
+// also the registry ZenML uses when none is configured.
+const dockerHubRegistry = "https://index.docker.io/v1/"
+
+// dockerLocalCredentials reads the credentials of a registry from the docker
+// config.json file. Credentials kept by a credential helper cannot be read.
+func dockerLocalCredentials(registry string) (map[string]string, string, error) {
+	file := filepath.Join(envOrHomePath("DOCKER_CONFIG", ".docker"), "config.json")
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, "", fmt.Errorf("unable to read docker config: %w", err)
+	}
+	var config struct {
+		Auths map[string]struct {
+			Auth     string `json:"auth"`
+			Username string `json:"username"`
+			Password string `json:"password"`
+		} `json:"auths"`
+		CredsStore  string            `json:"credsStore"`
+		CredHelpers map[string]string `json:"credHelpers"`
+	}
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, "", fmt.Errorf("unable to decode docker config %s: %w", file, err)
+	}
+
+	if registry == "" {
+		registries := make([]string, 0, len(config.Auths))
+		for r := range config.Auths {
+			registries = append(registries, r)
+		}
+		if len(registries) != 1 {
+			sort.Strings(registries)
+			return nil, "", fmt.Errorf(
+				"the docker config %s has credentials for %d registries, set docker_registry to one of: %s",
+				file, len(registries), strings.Join(registries, ", "),
+			)
+		}
+		registry = registries[0]
+	}
+
+	auth, ok := config.Auths[registry]
+	if helper := config.CredHelpers[registry]; helper != "" || (ok && auth.Auth == "" && auth.Password == "" && config.CredsStore != "") {
+		if helper == "" {
+			helper = config.CredsStore
+		}
+		return nil, "", fmt.Errorf(
+			"the credentials of registry %s are kept by the docker-credential-%s helper, which auto_configure cannot read",
+			registry, helper,
+		)
+	}
+	if !ok {
+		return nil, "", fmt.Errorf("the docker config %s has no credentials for registry %s", file, registry)
+	}
+
+	values := map[string]string{"username": auth.Username, "password": auth.Password}
+	if auth.Auth != "" {
+		decoded, err := base64.StdEncoding.DecodeString(auth.Auth)
+		if err != nil {
+			return nil, "", fmt.Errorf("unable to decode the credentials of registry %s: %w", registry, err)
+		}
+		values["username"], values["password"], _ = strings.Cut(string(decoded), ":")
+	}
+	if registry != dockerHubRegistry {
+		values["registry"] = registry
+	}
+	return values, fmt.Sprintf("the %s entry of %s", registry, file), nil
+}
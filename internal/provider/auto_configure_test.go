@@ -0,0 +1,212 @@
+package provider
+
+import (
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// clearCredentialEnv isolates a test from the credentials of the machine it
+// runs on.
+func clearCredentialEnv(t *testing.T) string {
+	t.Helper()
+
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	for _, name := range []string{
+		"AWS_ACCESS_KEY_ID", "AWS_SECRET_ACCESS_KEY", "AWS_SESSION_TOKEN", "AWS_PROFILE",
+		"AWS_REGION", "AWS_DEFAULT_REGION", "AWS_SHARED_CREDENTIALS_FILE", "AWS_CONFIG_FILE",
+		"GOOGLE_APPLICATION_CREDENTIALS", "GOOGLE_CLOUD_PROJECT",
+		"AZURE_TENANT_ID", "AZURE_CLIENT_ID", "AZURE_CLIENT_SECRET", "AZURE_SUBSCRIPTION_ID",
+		"KUBECONFIG", "DOCKER_CONFIG",
+	} {
+		t.Setenv(name, "")
+	}
+	return home
+}
+
+func writeTestFile(t *testing.T, name, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(name), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(name, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestAutoConfigure_AWSEnvironment(t *testing.T) {
+	clearCredentialEnv(t)
+	t.Setenv("AWS_ACCESS_KEY_ID", "AKIAEXAMPLE")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "secret")
+	t.Setenv("AWS_SESSION_TOKEN", "session")
+	t.Setenv("AWS_DEFAULT_REGION", "eu-west-1")
+
+	c, err := autoConfigure("aws", "secret-key", autoConfigureOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if c.Configuration["region"] != "eu-west-1" || len(c.Configuration) != 1 {
+		t.Errorf("expected only the region in the configuration, got %v", c.Configuration)
+	}
+	if c.Secrets["aws_access_key_id"] != "AKIAEXAMPLE" || c.Secrets["aws_secret_access_key"] != "secret" {
+		t.Errorf("expected the access key in the secrets, got %v", c.Secrets)
+	}
+	if _, ok := c.Secrets["aws_session_token"]; ok {
+		t.Errorf("expected the session token to be left out for the secret-key method")
+	}
+}
+
+func TestAutoConfigure_AWSProfile(t *testing.T) {
+	home := clearCredentialEnv(t)
+	writeTestFile(t, filepath.Join(home, ".aws", "credentials"), `
+[default]
+aws_access_key_id = AKIADEFAULT
+aws_secret_access_key = default-secret
+
+# CI credentials
+[ci]
+aws_access_key_id = AKIACI
+aws_secret_access_key = ci-secret
+aws_session_token = ci-session
+`)
+	writeTestFile(t, filepath.Join(home, ".aws", "config"), `
+[profile ci]
+region = us-west-2
+`)
+
+	c, err := autoConfigure("aws", "sts-token", autoConfigureOptions{AWSProfile: "ci"})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if c.Configuration["region"] != "us-west-2" || c.Secrets["aws_session_token"] != "ci-session" {
+		t.Errorf("expected the ci profile, got %v and %v", c.Configuration, c.Secrets)
+	}
+
+	_, err = autoConfigure("aws", "secret-key", autoConfigureOptions{})
+	if err == nil || !strings.Contains(err.Error(), "region") {
+		t.Errorf("expected an error about the missing region, got %v", err)
+	}
+}
+
+func TestAutoConfigure_GCPCredentialsFile(t *testing.T) {
+	home := clearCredentialEnv(t)
+	file := filepath.Join(home, "key.json")
+	writeTestFile(t, file, `{"type": "service_account", "project_id": "my-project"}`)
+	t.Setenv("GOOGLE_APPLICATION_CREDENTIALS", file)
+
+	c, err := autoConfigure("gcp", "service-account", autoConfigureOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if c.Configuration["project_id"] != "my-project" || !strings.Contains(c.Secrets["service_account_json"], "service_account") {
+		t.Errorf("unexpected configuration %v and secrets %v", c.Configuration, c.Secrets)
+	}
+
+	if _, err := autoConfigure("gcp", "user-account", autoConfigureOptions{}); err == nil {
+		t.Errorf("expected a service account file to be rejected for the user-account method")
+	}
+}
+
+func TestAutoConfigure_KubeconfigContext(t *testing.T) {
+	home := clearCredentialEnv(t)
+	ca := base64.StdEncoding.EncodeToString([]byte("-----BEGIN CERTIFICATE-----"))
+	writeTestFile(t, filepath.Join(home, ".kube", "config"), `
+current-context: dev
+contexts:
+  - name: dev
+    context: {cluster: dev-cluster, user: dev-user}
+  - name: prod
+    context: {cluster: prod-cluster, user: prod-user}
+clusters:
+  - name: dev-cluster
+    cluster: {server: "https://dev.example.com", insecure-skip-tls-verify: true}
+  - name: prod-cluster
+    cluster: {server: "https://prod.example.com", certificate-authority-data: `+ca+`}
+users:
+  - name: dev-user
+    user: {token: dev-token}
+  - name: prod-user
+    user: {username: admin, password: hunter2}
+`)
+
+	c, err := autoConfigure("kubernetes", "token", autoConfigureOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if c.Configuration["server"] != "https://dev.example.com" || c.Configuration["insecure"] != "true" || c.Secrets["token"] != "dev-token" {
+		t.Errorf("expected the current context, got %v and %v", c.Configuration, c.Secrets)
+	}
+
+	c, err = autoConfigure("kubernetes", "password", autoConfigureOptions{KubernetesContext: "prod"})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if c.Configuration["cluster_name"] != "prod-cluster" || c.Secrets["certificate_authority"] != "-----BEGIN CERTIFICATE-----" {
+		t.Errorf("expected the prod context, got %v and %v", c.Configuration, c.Secrets)
+	}
+
+	if _, err := autoConfigure("kubernetes", "token", autoConfigureOptions{KubernetesContext: "prod"}); err == nil {
+		t.Errorf("expected an error for a context without a token")
+	}
+}
+
+func TestAutoConfigure_DockerConfig(t *testing.T) {
+	home := clearCredentialEnv(t)
+	auth := base64.StdEncoding.EncodeToString([]byte("robot:s3cret"))
+	writeTestFile(t, filepath.Join(home, ".docker", "config.json"), `{
+  "auths": {
+    "ghcr.io": {"auth": "`+auth+`"},
+    "123456789012.dkr.ecr.us-east-1.amazonaws.com": {}
+  },
+  "credHelpers": {"123456789012.dkr.ecr.us-east-1.amazonaws.com": "ecr-login"}
+}`)
+
+	if _, err := autoConfigure("docker", "password", autoConfigureOptions{}); err == nil {
+		t.Errorf("expected an error when several registries are configured")
+	}
+
+	c, err := autoConfigure("docker", "password", autoConfigureOptions{DockerRegistry: "ghcr.io"})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if c.Configuration["username"] != "robot" || c.Configuration["registry"] != "ghcr.io" || c.Secrets["password"] != "s3cret" {
+		t.Errorf("unexpected configuration %v and secrets %v", c.Configuration, c.Secrets)
+	}
+
+	_, err = autoConfigure("docker", "password", autoConfigureOptions{DockerRegistry: "123456789012.dkr.ecr.us-east-1.amazonaws.com"})
+	if err == nil || !strings.Contains(err.Error(), "docker-credential-ecr-login") {
+		t.Errorf("expected an error about the credential helper, got %v", err)
+	}
+}
+
+func TestAutoConfigure_UnsupportedMethod(t *testing.T) {
+	clearCredentialEnv(t)
+
+	if _, err := autoConfigure("aws", "iam-role", autoConfigureOptions{}); err == nil || !strings.Contains(err.Error(), "secret-key, sts-token") {
+		t.Errorf("expected an error listing the supported methods, got %v", err)
+	}
+	if _, err := autoConfigure("hyperai", "rsa-key", autoConfigureOptions{}); err == nil {
+		t.Errorf("expected an error for a connector type without auto-configuration")
+	}
+}
+
+func TestAutoConfiguration_Fingerprint(t *testing.T) {
+	a := &autoConfiguration{Configuration: map[string]string{"region": "us-east-1"}, Secrets: map[string]string{"token": "a"}}
+	b := &autoConfiguration{Configuration: map[string]string{"region": "us-east-1"}, Secrets: map[string]string{"token": "b"}}
+	fingerprint := a.Fingerprint("aws", "secret-key", "aws")
+	if fingerprint != a.Fingerprint("aws", "secret-key", "aws") {
+		t.Errorf("expected the fingerprint to be stable")
+	}
+	if fingerprint == b.Fingerprint("aws", "secret-key", "aws") {
+		t.Errorf("expected the fingerprint to change with the secrets")
+	}
+	if fingerprint == a.Fingerprint("aws", "secret-key", "aws-prod") {
+		t.Errorf("expected the fingerprint to be salted with the connector")
+	}
+	if strings.Contains(fingerprint, "us-east-1") {
+		t.Errorf("expected the fingerprint not to contain the values")
+	}
+}
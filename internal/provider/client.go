@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -20,12 +21,43 @@ type ListParams struct {
 	Filter   map[string]string
 }
 
+// collectPages calls the given list function for every page of results and
+// returns the items of all pages.
+func collectPages[T any](
+	ctx context.Context,
+	params *ListParams,
+	list func(context.Context, *ListParams) (*Page[T], error),
+) ([]T, error) {
+	var items []T
+	for {
+		page, err := list(ctx, params)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, page.Items...)
+		if page.Index >= page.TotalPages {
+			return items, nil
+		}
+		params.Page = page.Index + 1
+	}
+}
+
+// ErrConflict is wrapped by the errors of create operations that fail
+// because an object with the same name already exists.
+var ErrConflict = errors.New("object already exists")
+
 type Client struct {
 	ServerURL       string
 	APIKey          string
 	APIToken        string
 	APITokenExpires *time.Time
 	HTTPClient      *http.Client
+	// AdoptExisting is the provider's adopt_existing setting, the default
+	// for resources that do not set it themselves.
+	AdoptExisting bool
+	// DefaultLabels are the labels added to every labelled object, see
+	// providerLabels.
+	DefaultLabels map[string]string
 }
 
 func NewClient(serverURL, apiKey string, apiToken string) *Client {
@@ -198,8 +230,11 @@ func (c *Client) GetServerInfo(ctx context.Context) (*ServerInfo, error) {
 // Stack operations
 func (c *Client) CreateStack(ctx context.Context, stack StackRequest) (*StackResponse, error) {
 	endpoint := "/api/v1/stacks"
-	resp, _, err := c.doRequest(ctx, "POST", endpoint, stack)
+	resp, status, err := c.doRequest(ctx, "POST", endpoint, stack)
 	if err != nil {
+		if status == http.StatusConflict {
+			return nil, fmt.Errorf("%w: %v", ErrConflict, err)
+		}
 		return nil, err
 	}
 	defer resp.Body.Close()
@@ -345,10 +380,18 @@ func (c *Client) RemoveComponentFromStack(
 		labels = stack.Metadata.Labels
 	}
 
+	// Environment is replaced as a whole on update, so the current value has
+	// to be sent back to avoid wiping it.
+	var environment map[string]string
+	if stack.Metadata != nil {
+		environment = stack.Metadata.Environment
+	}
+
 	update := StackUpdate{
-		Name:       stack.Name,
-		Components: newComponents,
-		Labels:     labels,
+		Name:        stack.Name,
+		Components:  newComponents,
+		Labels:      labels,
+		Environment: environment,
 	}
 
 	_, err = c.UpdateStack(ctx, stackID, update)
@@ -358,8 +401,11 @@ func (c *Client) RemoveComponentFromStack(
 // Component operations...
 func (c *Client) CreateComponent(ctx context.Context, component ComponentRequest) (*ComponentResponse, error) {
 	endpoint := "/api/v1/components"
-	resp, _, err := c.doRequest(ctx, "POST", endpoint, component)
+	resp, status, err := c.doRequest(ctx, "POST", endpoint, component)
 	if err != nil {
+		if status == http.StatusConflict {
+			return nil, fmt.Errorf("%w: %v", ErrConflict, err)
+		}
 		return nil, err
 	}
 	defer resp.Body.Close()
@@ -453,6 +499,50 @@ func (c *Client) ListStackComponents(ctx context.Context, params *ListParams) (*
 	return &result, nil
 }
 
+// ListComponentsByConnector returns all components that use the given
+// service connector.
+func (c *Client) ListComponentsByConnector(
+	ctx context.Context,
+	connectorID string,
+) ([]ComponentResponse, error) {
+	params := &ListParams{
+		Filter: map[string]string{
+			"connector_id": connectorID,
+		},
+	}
+	return collectPages(ctx, params, c.ListStackComponents)
+}
+
+// RemoveConnectorFromComponent clears the service connector and connector
+// resource ID of a component, keeping the rest of its settings unchanged.
+func (c *Client) RemoveConnectorFromComponent(
+	ctx context.Context,
+	componentID string,
+) error {
+	component, err := c.GetComponent(ctx, componentID)
+	if err != nil {
+		return err
+	}
+	if component == nil {
+		return fmt.Errorf("component %s not found", componentID)
+	}
+
+	update := ComponentUpdate{
+		Name: component.Name,
+	}
+	if component.Body != nil {
+		update.Type = component.Body.Type
+		update.Flavor = component.Body.Flavor
+	}
+	if component.Metadata != nil {
+		update.Configuration = component.Metadata.Configuration
+		update.Labels = component.Metadata.Labels
+	}
+
+	_, err = c.UpdateComponent(ctx, componentID, update)
+	return err
+}
+
 // Service Connector operations...
 func (c *Client) VerifyServiceConnector(ctx context.Context, connector ServiceConnectorRequest) (*ServiceConnectorResources, error) {
 	resp, _, err := c.doRequest(ctx, "POST", "/api/v1/service_connectors/verify", connector)
@@ -468,6 +558,40 @@ func (c *Client) VerifyServiceConnector(ctx context.Context, connector ServiceCo
 	return &result, nil
 }
 
+// VerifyExistingServiceConnector verifies the stored configuration of a
+// service connector and lists the resources it can access. The verification
+// can be narrowed to a resource type and to a single resource of that type.
+func (c *Client) VerifyExistingServiceConnector(
+	ctx context.Context,
+	id string,
+	resourceType string,
+	resourceID string,
+) (*ServiceConnectorResources, error) {
+	query := url.Values{}
+	if resourceType != "" {
+		query.Add("resource_type", resourceType)
+	}
+	if resourceID != "" {
+		query.Add("resource_id", resourceID)
+	}
+	path := fmt.Sprintf("/api/v1/service_connectors/%s/verify", id)
+	if len(query) > 0 {
+		path += "?" + query.Encode()
+	}
+
+	resp, _, err := c.doRequest(ctx, "PUT", path, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var result ServiceConnectorResources
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("error decoding response: %v", err)
+	}
+	return &result, nil
+}
+
 func (c *Client) CreateServiceConnector(ctx context.Context, connector ServiceConnectorRequest) (*ServiceConnectorResponse, error) {
 	endpoint := "/api/v1/service_connectors"
 	resp, _, err := c.doRequest(ctx, "POST", endpoint, connector)
@@ -661,6 +785,193 @@ func (c *Client) DeleteProject(ctx context.Context, nameOrID string) error {
 	return nil
 }
 
+func (c *Client) ListProjects(ctx context.Context, params *ListParams) (*Page[ProjectResponse], error) {
+	if params == nil {
+		params = &ListParams{
+			Page:     1,
+			PageSize: 100,
+		}
+	} else {
+		if params.Page <= 0 {
+			params.Page = 1
+		}
+		if params.PageSize <= 0 {
+			params.PageSize = 100
+		}
+	}
+
+	query := url.Values{}
+	query.Add("page", fmt.Sprintf("%d", params.Page))
+	query.Add("size", fmt.Sprintf("%d", params.PageSize))
+	for k, v := range params.Filter {
+		query.Add(k, v)
+	}
+
+	path := fmt.Sprintf("/api/v1/projects?%s", query.Encode())
+	resp, _, err := c.doRequest(ctx, "GET", path, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var result Page[ProjectResponse]
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("error decoding response: %v", err)
+	}
+
+	return &result, nil
+}
+
+// Flavor operations...
+func (c *Client) ListFlavors(ctx context.Context, params *ListParams) (*Page[FlavorResponse], error) {
+	if params == nil {
+		params = &ListParams{
+			Page:     1,
+			PageSize: 100,
+		}
+	} else {
+		if params.Page <= 0 {
+			params.Page = 1
+		}
+		if params.PageSize <= 0 {
+			params.PageSize = 100
+		}
+	}
+
+	query := url.Values{}
+	query.Add("page", fmt.Sprintf("%d", params.Page))
+	query.Add("size", fmt.Sprintf("%d", params.PageSize))
+	for k, v := range params.Filter {
+		query.Add(k, v)
+	}
+
+	path := fmt.Sprintf("/api/v1/flavors?%s", query.Encode())
+	resp, _, err := c.doRequest(ctx, "GET", path, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var result Page[FlavorResponse]
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("error decoding response: %v", err)
+	}
+
+	return &result, nil
+}
+
+// ListAllFlavors returns the flavors of all stack component types, including
+// their configuration schemas.
+func (c *Client) ListAllFlavors(ctx context.Context) ([]FlavorResponse, error) {
+	params := &ListParams{
+		Filter: map[string]string{
+			"hydrate": "true",
+		},
+	}
+	return collectPages(ctx, params, c.ListFlavors)
+}
+
+// Pipeline operations...
+func (c *Client) ListPipelines(ctx context.Context, params *ListParams) (*Page[PipelineResponse], error) {
+	if params == nil {
+		params = &ListParams{
+			Page:     1,
+			PageSize: 100,
+		}
+	} else {
+		if params.Page <= 0 {
+			params.Page = 1
+		}
+		if params.PageSize <= 0 {
+			params.PageSize = 100
+		}
+	}
+
+	query := url.Values{}
+	query.Add("page", fmt.Sprintf("%d", params.Page))
+	query.Add("size", fmt.Sprintf("%d", params.PageSize))
+	for k, v := range params.Filter {
+		query.Add(k, v)
+	}
+
+	path := fmt.Sprintf("/api/v1/pipelines?%s", query.Encode())
+	resp, _, err := c.doRequest(ctx, "GET", path, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var result Page[PipelineResponse]
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("error decoding response: %v", err)
+	}
+
+	return &result, nil
+}
+
+func (c *Client) DeletePipeline(ctx context.Context, id string) error {
+	resp, status, err := c.doRequest(ctx, "DELETE", fmt.Sprintf("/api/v1/pipelines/%s", id), nil)
+	if err != nil {
+		if status == 404 {
+			// Return nil if the pipeline is not found
+			return nil
+		}
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+func (c *Client) ListPipelineRuns(ctx context.Context, params *ListParams) (*Page[PipelineRunResponse], error) {
+	if params == nil {
+		params = &ListParams{
+			Page:     1,
+			PageSize: 100,
+		}
+	} else {
+		if params.Page <= 0 {
+			params.Page = 1
+		}
+		if params.PageSize <= 0 {
+			params.PageSize = 100
+		}
+	}
+
+	query := url.Values{}
+	query.Add("page", fmt.Sprintf("%d", params.Page))
+	query.Add("size", fmt.Sprintf("%d", params.PageSize))
+	for k, v := range params.Filter {
+		query.Add(k, v)
+	}
+
+	path := fmt.Sprintf("/api/v1/runs?%s", query.Encode())
+	resp, _, err := c.doRequest(ctx, "GET", path, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var result Page[PipelineRunResponse]
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("error decoding response: %v", err)
+	}
+
+	return &result, nil
+}
+
+func (c *Client) DeletePipelineRun(ctx context.Context, id string) error {
+	resp, status, err := c.doRequest(ctx, "DELETE", fmt.Sprintf("/api/v1/runs/%s", id), nil)
+	if err != nil {
+		if status == 404 {
+			// Return nil if the run is not found
+			return nil
+		}
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
 // Secret operations...
 func (c *Client) CreateSecret(ctx context.Context, secret SecretRequest) (*SecretResponse, error) {
 	resp, _, err := c.doRequest(ctx, "POST", "/api/v1/secrets", secret)
@@ -708,6 +1019,22 @@ func (c *Client) UpdateSecret(ctx context.Context, id string, secret SecretUpdat
 	return &result, nil
 }
 
+// PatchSecretValues sets or removes the given values of a secret, leaving its
+// other values unchanged.
+func (c *Client) PatchSecretValues(ctx context.Context, id string, values map[string]*string) (*SecretResponse, error) {
+	resp, _, err := c.doRequest(ctx, "PUT", fmt.Sprintf("/api/v1/secrets/%s?patch_values=true", id), SecretValuesPatch{Values: values})
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var result SecretResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("error decoding secret response: %v", err)
+	}
+	return &result, nil
+}
+
 func (c *Client) DeleteSecret(ctx context.Context, id string) error {
 	resp, status, err := c.doRequest(ctx, "DELETE", fmt.Sprintf("/api/v1/secrets/%s", id), nil)
 	if err != nil {
@@ -720,3 +1047,82 @@ func (c *Client) DeleteSecret(ctx context.Context, id string) error {
 	resp.Body.Close()
 	return nil
 }
+
+func (c *Client) ListSecrets(ctx context.Context, params *ListParams) (*Page[SecretResponse], error) {
+	if params == nil {
+		params = &ListParams{
+			Page:     1,
+			PageSize: 100,
+		}
+	} else {
+		if params.Page <= 0 {
+			params.Page = 1
+		}
+		if params.PageSize <= 0 {
+			params.PageSize = 100
+		}
+	}
+
+	query := url.Values{}
+	query.Add("page", fmt.Sprintf("%d", params.Page))
+	query.Add("size", fmt.Sprintf("%d", params.PageSize))
+	for k, v := range params.Filter {
+		query.Add(k, v)
+	}
+
+	path := fmt.Sprintf("/api/v1/secrets?%s", query.Encode())
+	resp, _, err := c.doRequest(ctx, "GET", path, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var result Page[SecretResponse]
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("error decoding secret response: %v", err)
+	}
+
+	return &result, nil
+}
+
+func (c *Client) GetSecretByName(ctx context.Context, name string) (*SecretResponse, error) {
+	params := &ListParams{
+		Filter: map[string]string{
+			"name": name,
+		},
+	}
+
+	secrets, err := c.ListSecrets(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(secrets.Items) == 0 {
+		return nil, nil
+	}
+
+	return &secrets.Items[0], nil
+}
+
+// ResolveSecretID returns the UUID of the secret identified by the given name
+// or ID, or an empty string if no such secret exists.
+func (c *Client) ResolveSecretID(ctx context.Context, nameOrID string) (string, error) {
+	if isUUID(nameOrID) {
+		secret, err := c.GetSecret(ctx, nameOrID)
+		if err != nil {
+			return "", err
+		}
+		if secret != nil {
+			return secret.ID, nil
+		}
+	}
+
+	secret, err := c.GetSecretByName(ctx, nameOrID)
+	if err != nil {
+		return "", err
+	}
+	if secret == nil {
+		return "", nil
+	}
+	return secret.ID, nil
+}
@@ -0,0 +1,124 @@
+// connector_expiry.go
+package provider
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+)
+
+// connectorExpiryWarningWindow is how long before the expiry of its
+// credentials a connector without renew_before is reported as expiring.
+const connectorExpiryWarningWindow = 24 * time.Hour
+
+// durationValidator validates that a string attribute holds a Go duration,
+// such as "30m" or "12h".
+type durationValidator struct{}
+
+func (v durationValidator) Description(ctx context.Context) string {
+	return "value must be a duration such as 30m or 12h"
+}
+
+func (v durationValidator) MarkdownDescription(ctx context.Context) string {
+	return "value must be a duration such as `30m` or `12h`"
+}
+
+func (v durationValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+	d, err := time.ParseDuration(req.ConfigValue.ValueString())
+	if err != nil || d < 0 {
+		resp.Diagnostics.AddAttributeError(
+			req.Path,
+			"Invalid Duration",
+			fmt.Sprintf("The value of %s must be a positive duration such as \"30m\" or \"12h\", got %q.",
+				req.Path, req.ConfigValue.ValueString()),
+		)
+	}
+}
+
+// expiresAtLayout is the layout of the naive ISO 8601 timestamps, in UTC,
+// that ZenML returns for expires_at, such as 2025-01-01T12:00:00.123456.
+const expiresAtLayout = "2006-01-02T15:04:05.999999999"
+
+// parseExpiresAt parses the expires_at value of a connector, which is either a
+// naive ZenML timestamp or an RFC 3339 timestamp with an offset.
+func parseExpiresAt(value string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339Nano, value); err == nil {
+		return t, nil
+	}
+	return time.ParseInLocation(expiresAtLayout, value, time.UTC)
+}
+
+// checkCredentialExpiry warns about a connector whose credentials expire
+// within renew_before, or within a day without it, and reports whether the
+// plan must force an update to renew them. Credentials that changed are sent
+// by the update the plan already holds. Write-only secrets are not visible
+// at plan time, so within renew_before the update is forced and sends the
+// current secrets_wo values, which are expected to come from a source that
+// issues fresh credentials, such as an ephemeral resource. Resending
+// unchanged secret_configuration or auto_configure credentials would not
+// renew them, so those only get a warning.
+func checkCredentialExpiry(plan, state *ServiceConnectorResourceModel, renewed, writeOnly bool, diags *diag.Diagnostics) bool {
+	if state.ExpiresAt.IsNull() || state.ExpiresAt.IsUnknown() || plan.RenewBefore.IsUnknown() {
+		return false
+	}
+	expires := state.ExpiresAt.ValueString()
+	expiresAt, err := parseExpiresAt(expires)
+	if err != nil {
+		diags.AddAttributeWarning(
+			path.Root("expires_at"),
+			"Unable to Check Service Connector Expiry",
+			fmt.Sprintf("The expiry time %q of the service connector could not be parsed, so its "+
+				"credentials are not checked for expiry: %s", expires, err),
+		)
+		return false
+	}
+
+	window := connectorExpiryWarningWindow
+	renewBefore := !plan.RenewBefore.IsNull()
+	if renewBefore {
+		if window, err = time.ParseDuration(plan.RenewBefore.ValueString()); err != nil {
+			return false
+		}
+	}
+
+	remaining := time.Until(expiresAt)
+	if remaining > window {
+		return false
+	}
+
+	var detail string
+	if remaining <= 0 {
+		detail = fmt.Sprintf("The credentials of service connector '%s' expired at %s.", plan.Name.ValueString(), expires)
+	} else {
+		detail = fmt.Sprintf("The credentials of service connector '%s' expire at %s, in %s.",
+			plan.Name.ValueString(), expires, remaining.Round(time.Minute))
+	}
+
+	renew := false
+	switch {
+	case renewed:
+		detail += " The plan updates the connector with the new credentials."
+	case renewBefore && writeOnly:
+		renew = true
+		detail += " The plan updates the connector with the current secrets_wo values; " +
+			"make sure they hold fresh credentials."
+	case renewBefore:
+		detail += " The configured credentials have not changed, so updating the connector would " +
+			"not renew them. Provide new credentials by changing secret_configuration or by " +
+			"refreshing the local credentials read by auto_configure, or set them in secrets_wo " +
+			"from a source that issues fresh credentials, which renew_before then renews automatically."
+	default:
+		detail += " Set renew_before to renew credentials set in secrets_wo before they expire, " +
+			"or provide new credentials by changing secret_configuration or by refreshing the " +
+			"local credentials read by auto_configure."
+	}
+	diags.AddAttributeWarning(path.Root("expires_at"), "Service Connector Credentials Expiring", detail)
+	return renew
+}
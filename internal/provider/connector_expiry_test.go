@@ -0,0 +1,115 @@
+package provider
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// testCredentialRenewal plans an update of a connector whose state expires at
+// expiresAt, with the given secret configuration in the plan and write-only
+// secrets in the configuration.
+func testCredentialRenewal(t *testing.T, expiresAt string, renewBefore types.String, secrets, writeOnly map[string]string) *resource.ModifyPlanResponse {
+	t.Helper()
+	ctx := context.Background()
+
+	schemaResp := &resource.SchemaResponse{}
+	(&ServiceConnectorResource{}).Schema(ctx, resource.SchemaRequest{}, schemaResp)
+	s := schemaResp.Schema
+
+	raw := nullAttributesValue(s.Type().TerraformType(ctx))
+	state := tfsdk.State{Schema: s, Raw: raw.Copy()}
+	diags := state.SetAttribute(ctx, path.Root("name"), "aws")
+	diags.Append(state.SetAttribute(ctx, path.Root("expires_at"), expiresAt)...)
+	diags.Append(state.SetAttribute(ctx, path.Root("renew_before"), renewBefore)...)
+	diags.Append(state.SetAttribute(ctx, path.Root("secret_configuration"), map[string]string{"aws_secret_access_key": "old"})...)
+
+	plan := tfsdk.Plan{Schema: s, Raw: state.Raw.Copy()}
+	if secrets != nil {
+		diags.Append(plan.SetAttribute(ctx, path.Root("secret_configuration"), secrets)...)
+	}
+	config := tfsdk.Plan{Schema: s, Raw: plan.Raw.Copy()}
+	if writeOnly != nil {
+		diags.Append(config.SetAttribute(ctx, path.Root("secrets_wo"), writeOnly)...)
+	}
+	if diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+
+	resp := &resource.ModifyPlanResponse{Plan: plan}
+	(&ServiceConnectorResource{}).planCredentialRenewal(ctx, resource.ModifyPlanRequest{
+		Config: tfsdk.Config{Schema: s, Raw: config.Raw},
+		State:  state,
+		Plan:   plan,
+	}, resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+	}
+	return resp
+}
+
+// serverTime formats a time like the naive UTC timestamps of the ZenML server.
+func serverTime(t time.Time) string {
+	return t.UTC().Format("2006-01-02T15:04:05.000000")
+}
+
+func TestCredentialRenewal(t *testing.T) {
+	tests := []struct {
+		name        string
+		expiresAt   string
+		renewBefore types.String
+		secrets     map[string]string
+		writeOnly   map[string]string
+		warning     string
+		renew       bool
+	}{
+		{"outside window", serverTime(time.Now().Add(2 * time.Hour)), types.StringValue("1h"), nil, nil, "", false},
+		{"unchanged credentials", serverTime(time.Now().Add(30 * time.Minute)), types.StringValue("1h"), nil, nil, "have not changed", false},
+		{"new credentials", serverTime(time.Now().Add(30 * time.Minute)), types.StringValue("1h"), map[string]string{"aws_secret_access_key": "new"}, nil, "new credentials", false},
+		{"write-only credentials", serverTime(time.Now().Add(30 * time.Minute)), types.StringValue("1h"), nil, map[string]string{"aws_session_token": "fresh"}, "secrets_wo", true},
+		{"write-only credentials without renew_before", serverTime(time.Now().Add(30 * time.Minute)), types.StringNull(), nil, map[string]string{"aws_session_token": "fresh"}, "Set renew_before", false},
+		{"expired without renew_before", serverTime(time.Now().Add(-time.Hour)), types.StringNull(), nil, nil, "expired at", false},
+		{"unparseable expiry", "tomorrow", types.StringNull(), nil, nil, "could not be parsed", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := testCredentialRenewal(t, tt.expiresAt, tt.renewBefore, tt.secrets, tt.writeOnly)
+
+			warnings := resp.Diagnostics.Warnings()
+			if tt.warning == "" && len(warnings) != 0 {
+				t.Errorf("expected no warning, got %v", warnings)
+			}
+			if tt.warning != "" && (len(warnings) != 1 || !strings.Contains(warnings[0].Detail(), tt.warning)) {
+				t.Errorf("expected a warning containing %q, got %v", tt.warning, warnings)
+			}
+
+			// Changed configuration updates the connector, which the
+			// framework plans on its own. Only write-only credentials within
+			// renew_before force an update.
+			var expires types.String
+			resp.Diagnostics.Append(resp.Plan.GetAttribute(context.Background(), path.Root("expires_at"), &expires)...)
+			if tt.renew != expires.IsUnknown() || (!tt.renew && expires.ValueString() != tt.expiresAt) {
+				t.Errorf("expected renewal %t, got expires_at %s", tt.renew, expires)
+			}
+		})
+	}
+}
+
+func TestParseExpiresAt(t *testing.T) {
+	want := time.Date(2025, 1, 1, 12, 0, 0, 123456000, time.UTC)
+	for _, value := range []string{"2025-01-01T12:00:00.123456", "2025-01-01T12:00:00.123456Z", "2025-01-01T13:00:00.123456+01:00"} {
+		got, err := parseExpiresAt(value)
+		if err != nil || !got.Equal(want) {
+			t.Errorf("expected %q to be parsed as %s, got %s, %v", value, want, got, err)
+		}
+	}
+	if _, err := parseExpiresAt("tomorrow"); err == nil {
+		t.Errorf("expected an error for an invalid timestamp")
+	}
+}
@@ -0,0 +1,159 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+var _ datasource.DataSource = &ActiveStackDataSource{}
+
+func NewActiveStackDataSource() datasource.DataSource {
+	return &ActiveStackDataSource{}
+}
+
+type ActiveStackDataSource struct {
+	client *Client
+}
+
+type ActiveStackDataSourceModel struct {
+	ID        types.String `tfsdk:"id"`
+	Name      types.String `tfsdk:"name"`
+	Project   types.String `tfsdk:"project"`
+	ProjectID types.String `tfsdk:"project_id"`
+	UserID    types.String `tfsdk:"user_id"`
+}
+
+func (d *ActiveStackDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_active_stack"
+}
+
+func (d *ActiveStackDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Data source for the stack that is active for the " +
+			"authenticated user in a project",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				MarkdownDescription: "ID of the active stack",
+				Computed:            true,
+			},
+			"name": schema.StringAttribute{
+				MarkdownDescription: "Name of the active stack",
+				Computed:            true,
+			},
+			"project": schema.StringAttribute{
+				MarkdownDescription: "Name or ID of the project. Defaults to the " +
+					"default project of the authenticated user.",
+				Optional: true,
+			},
+			"project_id": schema.StringAttribute{
+				MarkdownDescription: "ID of the project",
+				Computed:            true,
+			},
+			"user_id": schema.StringAttribute{
+				MarkdownDescription: "ID of the authenticated user",
+				Computed:            true,
+			},
+		},
+	}
+}
+
+func (d *ActiveStackDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*Client)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.client = client
+}
+
+func (d *ActiveStackDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data ActiveStackDataSourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Debug(ctx, "Reading active stack information")
+
+	user, err := d.client.GetCurrentUser(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to get current user, got error: %s", err))
+		return
+	}
+	data.UserID = types.StringValue(user.ID)
+
+	projectNameOrID := data.Project.ValueString()
+	if data.Project.IsNull() || projectNameOrID == "" {
+		if user.Body == nil || user.Body.DefaultProjectID == nil {
+			resp.Diagnostics.AddError(
+				"Missing Required Attribute",
+				"The authenticated user has no default project, 'project' must be specified",
+			)
+			return
+		}
+		projectNameOrID = *user.Body.DefaultProjectID
+	}
+
+	project, err := d.client.GetProject(ctx, projectNameOrID)
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read project, got error: %s", err))
+		return
+	}
+
+	if project == nil {
+		resp.Diagnostics.AddError(
+			"Project Not Found",
+			fmt.Sprintf("No project found with name or ID %q", projectNameOrID),
+		)
+		return
+	}
+	data.ProjectID = types.StringValue(project.ID)
+
+	if project.Body == nil || project.Body.DefaultStackID == nil {
+		resp.Diagnostics.AddError(
+			"Active Stack Not Found",
+			fmt.Sprintf("Project %q has no default stack", project.Name),
+		)
+		return
+	}
+
+	stack, err := d.client.GetStack(ctx, *project.Body.DefaultStackID)
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read stack, got error: %s", err))
+		return
+	}
+
+	if stack == nil {
+		resp.Diagnostics.AddError(
+			"Active Stack Not Found",
+			fmt.Sprintf("The default stack of project %q no longer exists", project.Name),
+		)
+		return
+	}
+
+	data.ID = types.StringValue(stack.ID)
+	data.Name = types.StringValue(stack.Name)
+
+	tflog.Trace(ctx, "read a data source")
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
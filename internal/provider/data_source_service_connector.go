@@ -28,6 +28,7 @@ type ServiceConnectorDataSourceModel struct {
 	Type          types.String `tfsdk:"type"`
 	AuthMethod    types.String `tfsdk:"auth_method"`
 	ResourceType  types.String `tfsdk:"resource_type"`
+	ResourceTypes types.Set    `tfsdk:"resource_types"`
 	ResourceID    types.String `tfsdk:"resource_id"`
 	Configuration types.Map    `tfsdk:"configuration"`
 	Labels        types.Map    `tfsdk:"labels"`
@@ -65,6 +66,11 @@ func (d *ServiceConnectorDataSource) Schema(ctx context.Context, req datasource.
 				MarkdownDescription: "Resource type associated with the service connector",
 				Computed:            true,
 			},
+			"resource_types": schema.SetAttribute{
+				MarkdownDescription: "All resource types the service connector can be used for",
+				ElementType:         types.StringType,
+				Computed:            true,
+			},
 			"resource_id": schema.StringAttribute{
 				MarkdownDescription: "Resource ID for the service connector",
 				Computed:            true,
@@ -181,6 +187,9 @@ func (d *ServiceConnectorDataSource) Read(ctx context.Context, req datasource.Re
 		} else {
 			data.ResourceType = types.StringNull()
 		}
+		resourceTypes, setDiags := types.SetValueFrom(ctx, types.StringType, connector.Body.ResourceTypes)
+		resp.Diagnostics.Append(setDiags...)
+		data.ResourceTypes = resourceTypes
 
 		if connector.Body.ResourceID != nil {
 			data.ResourceID = types.StringValue(*connector.Body.ResourceID)
@@ -22,12 +22,14 @@ type StackDataSource struct {
 }
 
 type StackDataSourceModel struct {
-	ID         types.String `tfsdk:"id"`
-	Name       types.String `tfsdk:"name"`
-	Components types.List   `tfsdk:"components"`
-	Labels     types.Map    `tfsdk:"labels"`
-	Created    types.String `tfsdk:"created"`
-	Updated    types.String `tfsdk:"updated"`
+	ID          types.String `tfsdk:"id"`
+	Name        types.String `tfsdk:"name"`
+	Components  types.List   `tfsdk:"components"`
+	Labels      types.Map    `tfsdk:"labels"`
+	Environment types.Map    `tfsdk:"environment"`
+	Secrets     types.List   `tfsdk:"secrets"`
+	Created     types.String `tfsdk:"created"`
+	Updated     types.String `tfsdk:"updated"`
 }
 
 type StackComponentModel struct {
@@ -83,6 +85,16 @@ func (d *StackDataSource) Schema(ctx context.Context, req datasource.SchemaReque
 				ElementType:         types.StringType,
 				Computed:            true,
 			},
+			"environment": schema.MapAttribute{
+				MarkdownDescription: "Environment variables set for pipeline steps running on the stack",
+				ElementType:         types.StringType,
+				Computed:            true,
+			},
+			"secrets": schema.ListAttribute{
+				MarkdownDescription: "IDs of the secrets attached to the stack",
+				ElementType:         types.StringType,
+				Computed:            true,
+			},
 			"created": schema.StringAttribute{
 				MarkdownDescription: "The timestamp when the stack was created",
 				Computed:            true,
@@ -214,6 +226,26 @@ func (d *StackDataSource) Read(ctx context.Context, req datasource.ReadRequest,
 		data.Labels = types.MapNull(types.StringType)
 	}
 
+	if stack.Metadata != nil && stack.Metadata.Environment != nil {
+		environmentValue, diags := types.MapValueFrom(ctx, types.StringType, stack.Metadata.Environment)
+		resp.Diagnostics.Append(diags...)
+		if !resp.Diagnostics.HasError() {
+			data.Environment = environmentValue
+		}
+	} else {
+		data.Environment = types.MapNull(types.StringType)
+	}
+
+	if stack.Metadata != nil && stack.Metadata.Secrets != nil {
+		secretsValue, diags := types.ListValueFrom(ctx, types.StringType, stack.Metadata.Secrets)
+		resp.Diagnostics.Append(diags...)
+		if !resp.Diagnostics.HasError() {
+			data.Secrets = secretsValue
+		}
+	} else {
+		data.Secrets = types.ListNull(types.StringType)
+	}
+
 	tflog.Trace(ctx, "read a data source")
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
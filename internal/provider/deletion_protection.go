@@ -0,0 +1,136 @@
+// deletion_protection.go
+package provider
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// deletionProtectionEnvVar can be set to "all" to protect every resource
+// managed by the provider from deletion, regardless of the value of their
+// deletion_protection attribute.
+const deletionProtectionEnvVar = "ZENML_TF_DELETION_PROTECTION"
+
+func deletionProtectionAttribute() schema.BoolAttribute {
+	return schema.BoolAttribute{
+		MarkdownDescription: "Whether Terraform is prevented from deleting or " +
+			"replacing the resource. Must be set to `false` and applied " +
+			"before the resource can be destroyed. Setting the `" +
+			deletionProtectionEnvVar + "` environment variable to `all` " +
+			"protects every resource.",
+		Optional: true,
+		Computed: true,
+		Default:  booldefault.StaticBool(false),
+	}
+}
+
+// deletionProtectionEnforced reports whether deletion protection is enabled
+// for all resources through the environment.
+func deletionProtectionEnforced() bool {
+	return strings.EqualFold(strings.TrimSpace(os.Getenv(deletionProtectionEnvVar)), "all")
+}
+
+// isDeletionProtected reports whether a resource with the given
+// deletion_protection value must not be deleted.
+func isDeletionProtected(value types.Bool) bool {
+	return deletionProtectionEnforced() || value.ValueBool()
+}
+
+func addDeletionProtectionError(diags *diag.Diagnostics, kind, name, action string) {
+	if deletionProtectionEnforced() {
+		diags.AddError(
+			"Deletion Protection Enabled",
+			fmt.Sprintf(
+				"Cannot %s %s %q because deletion protection is enforced "+
+					"for all resources by the %s environment variable. "+
+					"Unset the environment variable to %s the %s.",
+				action, kind, name, deletionProtectionEnvVar, action, kind,
+			),
+		)
+		return
+	}
+	diags.AddAttributeError(
+		path.Root("deletion_protection"),
+		"Deletion Protection Enabled",
+		fmt.Sprintf(
+			"Cannot %s %s %q because deletion_protection is set to true. "+
+				"Set deletion_protection to false and apply the change "+
+				"before trying to %s the %s.",
+			action, kind, name, action, kind,
+		),
+	)
+}
+
+// planChangesAttributes reports whether any of the given attributes has a
+// known planned value that differs from its value in state. It is used to
+// detect plans that replace a resource, since attribute-level RequiresReplace
+// plan modifiers are not visible to resource-level plan modification.
+func planChangesAttributes(
+	ctx context.Context,
+	req resource.ModifyPlanRequest,
+	diags *diag.Diagnostics,
+	paths ...path.Path,
+) bool {
+	for _, p := range paths {
+		var stateValue, planValue attr.Value
+		diags.Append(req.State.GetAttribute(ctx, p, &stateValue)...)
+		diags.Append(req.Plan.GetAttribute(ctx, p, &planValue)...)
+		if diags.HasError() {
+			return false
+		}
+		if planValue.IsUnknown() {
+			continue
+		}
+		if !stateValue.Equal(planValue) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkDeletionProtection fails destroy plans, and plans that replace the
+// resource when replace is true, for resources with deletion protection.
+func checkDeletionProtection(
+	ctx context.Context,
+	req resource.ModifyPlanRequest,
+	resp *resource.ModifyPlanResponse,
+	kind string,
+	nameAttribute string,
+	replace bool,
+) {
+	if req.State.Raw.IsNull() {
+		// Nothing to protect while the resource is being created.
+		return
+	}
+	if !req.Plan.Raw.IsNull() && !replace {
+		return
+	}
+
+	var protected types.Bool
+	var name types.String
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("deletion_protection"), &protected)...)
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root(nameAttribute), &name)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !isDeletionProtected(protected) {
+		return
+	}
+
+	action := "delete"
+	if !req.Plan.Raw.IsNull() {
+		action = "replace"
+	}
+	addDeletionProtectionError(&resp.Diagnostics, kind, name.ValueString(), action)
+}
@@ -0,0 +1,99 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func testStackComponentPlanValue(t *testing.T, flavor string, protected bool) (tfsdk.Plan, tfsdk.State) {
+	t.Helper()
+	ctx := context.Background()
+
+	schemaResp := &resource.SchemaResponse{}
+	(&StackComponentResource{}).Schema(ctx, resource.SchemaRequest{}, schemaResp)
+	if schemaResp.Diagnostics.HasError() {
+		t.Fatalf("unexpected schema diagnostics: %v", schemaResp.Diagnostics)
+	}
+
+	objectType := schemaResp.Schema.Type().TerraformType(ctx).(tftypes.Object)
+	values := make(map[string]tftypes.Value, len(objectType.AttributeTypes))
+	for name, attrType := range objectType.AttributeTypes {
+		values[name] = tftypes.NewValue(attrType, nil)
+	}
+	values["id"] = tftypes.NewValue(tftypes.String, "component-id")
+	values["name"] = tftypes.NewValue(tftypes.String, "test-store")
+	values["type"] = tftypes.NewValue(tftypes.String, "artifact_store")
+	values["flavor"] = tftypes.NewValue(tftypes.String, flavor)
+	values["deletion_protection"] = tftypes.NewValue(tftypes.Bool, protected)
+
+	raw := tftypes.NewValue(objectType, values)
+	return tfsdk.Plan{Schema: schemaResp.Schema, Raw: raw},
+		tfsdk.State{Schema: schemaResp.Schema, Raw: raw}
+}
+
+func TestDeletionProtection_BlocksDestroyPlan(t *testing.T) {
+	ctx := context.Background()
+	plan, state := testStackComponentPlanValue(t, "local", true)
+
+	resp := &resource.ModifyPlanResponse{Plan: plan}
+	(&StackComponentResource{}).ModifyPlan(ctx, resource.ModifyPlanRequest{
+		State: state,
+		Plan:  tfsdk.Plan{Schema: plan.Schema, Raw: tftypes.NewValue(plan.Raw.Type(), nil)},
+	}, resp)
+
+	if !resp.Diagnostics.HasError() {
+		t.Fatal("expected destroy plan of a protected component to fail")
+	}
+}
+
+func TestDeletionProtection_BlocksReplacePlan(t *testing.T) {
+	ctx := context.Background()
+	_, state := testStackComponentPlanValue(t, "local", true)
+	plan, _ := testStackComponentPlanValue(t, "s3", true)
+
+	resp := &resource.ModifyPlanResponse{Plan: plan}
+	(&StackComponentResource{}).ModifyPlan(ctx, resource.ModifyPlanRequest{
+		State: state,
+		Plan:  plan,
+	}, resp)
+
+	if !resp.Diagnostics.HasError() {
+		t.Fatal("expected replace plan of a protected component to fail")
+	}
+}
+
+func TestDeletionProtection_AllowsInPlaceUpdate(t *testing.T) {
+	ctx := context.Background()
+	plan, state := testStackComponentPlanValue(t, "local", true)
+
+	resp := &resource.ModifyPlanResponse{Plan: plan}
+	(&StackComponentResource{}).ModifyPlan(ctx, resource.ModifyPlanRequest{
+		State: state,
+		Plan:  plan,
+	}, resp)
+
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+	}
+}
+
+func TestDeletionProtection_EnvironmentOverride(t *testing.T) {
+	ctx := context.Background()
+	t.Setenv(deletionProtectionEnvVar, "all")
+
+	plan, state := testStackComponentPlanValue(t, "local", false)
+
+	resp := &resource.ModifyPlanResponse{Plan: plan}
+	(&StackComponentResource{}).ModifyPlan(ctx, resource.ModifyPlanRequest{
+		State: state,
+		Plan:  tfsdk.Plan{Schema: plan.Schema, Raw: tftypes.NewValue(plan.Raw.Type(), nil)},
+	}, resp)
+
+	if !resp.Diagnostics.HasError() {
+		t.Fatalf("expected %s=all to protect every resource", deletionProtectionEnvVar)
+	}
+}
@@ -0,0 +1,435 @@
+// export.go
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/zclconf/go-cty/cty"
+
+	"terraform-provider-zenml/internal/flavorgen"
+)
+
+var nonIdentifierChars = regexp.MustCompile(`[^a-z0-9_]+`)
+
+// exportNames hands out unique Terraform resource names per resource type.
+type exportNames map[string]bool
+
+func (n exportNames) name(resourceType, objectName string) string {
+	base := strings.Trim(nonIdentifierChars.ReplaceAllString(strings.ToLower(objectName), "_"), "_")
+	if base == "" {
+		base = "unnamed"
+	}
+	if base[0] >= '0' && base[0] <= '9' {
+		base = "_" + base
+	}
+
+	name := base
+	for i := 2; n[resourceType+"."+name]; i++ {
+		name = fmt.Sprintf("%s_%d", base, i)
+	}
+	n[resourceType+"."+name] = true
+	return name
+}
+
+// exporter collects the objects of a ZenML server and renders them as HCL.
+type exporter struct {
+	client *Client
+	file   *hclwrite.File
+	names  exportNames
+
+	// Terraform addresses of exported objects, keyed by object ID, used to
+	// turn IDs into resource references.
+	connectors map[string]hcl.Traversal
+	components map[string]hcl.Traversal
+	secrets    map[string]hcl.Traversal
+	stacks     map[string]hcl.Traversal
+}
+
+// Export writes Terraform configuration for every project, service connector,
+// secret, stack component and stack on the ZenML server, together with the
+// import blocks needed to adopt them. References between objects are written
+// as resource references. Secret values are never exported.
+func Export(ctx context.Context, client *Client, w io.Writer) error {
+	e := &exporter{
+		client:     client,
+		file:       hclwrite.NewEmptyFile(),
+		names:      exportNames{},
+		connectors: map[string]hcl.Traversal{},
+		components: map[string]hcl.Traversal{},
+		secrets:    map[string]hcl.Traversal{},
+		stacks:     map[string]hcl.Traversal{},
+	}
+
+	// Objects are exported in dependency order, so that references can be
+	// resolved against the objects exported before them.
+	steps := []func(context.Context) error{
+		e.exportServiceConnectors,
+		e.exportSecrets,
+		e.exportStackComponents,
+		e.exportStacks,
+		e.exportProjects,
+	}
+	for _, step := range steps {
+		if err := step(ctx); err != nil {
+			return err
+		}
+	}
+
+	_, err := w.Write(e.file.Bytes())
+	return err
+}
+
+// addResource appends an import block and an empty resource block for the
+// object with the given ID and returns the resource body and its address.
+func (e *exporter) addResource(resourceType, objectName, id string) (*hclwrite.Body, hcl.Traversal) {
+	address := hcl.Traversal{
+		hcl.TraverseRoot{Name: resourceType},
+		hcl.TraverseAttr{Name: e.names.name(resourceType, objectName)},
+	}
+
+	body := e.file.Body()
+	importBlock := body.AppendNewBlock("import", nil)
+	importBlock.Body().SetAttributeTraversal("to", address)
+	importBlock.Body().SetAttributeValue("id", cty.StringVal(id))
+	body.AppendNewline()
+
+	block := body.AppendNewBlock("resource", []string{resourceType, address[1].(hcl.TraverseAttr).Name})
+	body.AppendNewline()
+	return block.Body(), address
+}
+
+func (e *exporter) exportServiceConnectors(ctx context.Context) error {
+	items, err := collectPages(ctx, &ListParams{}, e.client.ListServiceConnectors)
+	if err != nil {
+		return fmt.Errorf("error listing service connectors: %w", err)
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].Name < items[j].Name })
+
+	for _, item := range items {
+		connector, err := e.client.GetServiceConnector(ctx, item.ID)
+		if err != nil {
+			return fmt.Errorf("error reading service connector %s: %w", item.Name, err)
+		}
+		if connector == nil {
+			continue
+		}
+
+		body, address := e.addResource("zenml_service_connector", connector.Name, connector.ID)
+		e.connectors[connector.ID] = address
+		body.SetAttributeValue("name", cty.StringVal(connector.Name))
+		if connector.Body != nil {
+			body.SetAttributeValue("type", cty.StringVal(connectorTypeName(connector.Body.ConnectorType)))
+			body.SetAttributeValue("auth_method", cty.StringVal(connector.Body.AuthMethod))
+			switch len(connector.Body.ResourceTypes) {
+			case 0:
+			case 1:
+				body.SetAttributeValue("resource_type", cty.StringVal(connector.Body.ResourceTypes[0]))
+			default:
+				resourceTypes := make([]cty.Value, len(connector.Body.ResourceTypes))
+				for i, t := range connector.Body.ResourceTypes {
+					resourceTypes[i] = cty.StringVal(t)
+				}
+				body.SetAttributeValue("resource_types", cty.SetVal(resourceTypes))
+			}
+			if connector.Body.ResourceID != nil {
+				body.SetAttributeValue("resource_id", cty.StringVal(*connector.Body.ResourceID))
+			}
+		}
+		if connector.Metadata != nil {
+			setStringMapAttribute(body, "configuration", exportConfiguration(connector.Metadata.Configuration))
+			setStringMapAttribute(body, "labels", connector.Metadata.Labels)
+			if connector.Metadata.SecretID != nil {
+				appendComment(body, "Secret configuration values are not exported. The connector keeps\n"+
+					"its stored values until they are set in `secret_configuration`, or in\n"+
+					"`secrets_wo` together with `secrets_wo_version`.")
+			}
+		}
+	}
+	return nil
+}
+
+func (e *exporter) exportSecrets(ctx context.Context) error {
+	items, err := collectPages(ctx, &ListParams{}, e.client.ListSecrets)
+	if err != nil {
+		return fmt.Errorf("error listing secrets: %w", err)
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].Name < items[j].Name })
+
+	for _, secret := range items {
+		body, address := e.addResource("zenml_secret", secret.Name, secret.ID)
+		e.secrets[secret.ID] = address
+		body.SetAttributeValue("name", cty.StringVal(secret.Name))
+		if secret.Body != nil && secret.Body.Private {
+			body.SetAttributeValue("private", cty.True)
+		}
+
+		// Secret values are not exported. The imported values are kept on the
+		// server until values are added here and ignore_changes is removed.
+		body.SetAttributeValue("values", cty.MapValEmpty(cty.String))
+		lifecycle := body.AppendNewBlock("lifecycle", nil)
+		lifecycle.Body().SetAttributeRaw("ignore_changes", hclwrite.TokensForTuple(
+			[]hclwrite.Tokens{hclwrite.TokensForIdentifier("values")},
+		))
+	}
+	return nil
+}
+
+func (e *exporter) exportStackComponents(ctx context.Context) error {
+	items, err := collectPages(ctx, &ListParams{}, e.client.ListStackComponents)
+	if err != nil {
+		return fmt.Errorf("error listing stack components: %w", err)
+	}
+
+	components := make([]*ComponentResponse, 0, len(items))
+	for _, item := range items {
+		component, err := e.client.GetComponent(ctx, item.ID)
+		if err != nil {
+			return fmt.Errorf("error reading stack component %s: %w", item.Name, err)
+		}
+		if component != nil && component.Body != nil {
+			components = append(components, component)
+		}
+	}
+	// The flavor schemas tell which configuration keys hold secrets, which
+	// are left out like the secret values of connectors.
+	flavors, err := e.client.ListAllFlavors(ctx)
+	if err != nil {
+		return fmt.Errorf("error listing flavors: %w", err)
+	}
+	sensitiveKeys := make(map[string]map[string]bool, len(flavors))
+	for _, flavor := range flavors {
+		if flavor.Body != nil && flavor.Metadata != nil {
+			sensitiveKeys[flavor.Body.Type+"/"+flavor.Name] = flavorgen.SensitiveKeys(flavor.Metadata.ConfigSchema)
+		}
+	}
+
+	sort.Slice(components, func(i, j int) bool {
+		if components[i].Body.Type != components[j].Body.Type {
+			return components[i].Body.Type < components[j].Body.Type
+		}
+		return components[i].Name < components[j].Name
+	})
+
+	for _, component := range components {
+		body, address := e.addResource(
+			"zenml_stack_component",
+			component.Body.Type+"_"+component.Name,
+			component.ID,
+		)
+		e.components[component.ID] = address
+		body.SetAttributeValue("name", cty.StringVal(component.Name))
+		body.SetAttributeValue("type", cty.StringVal(component.Body.Type))
+		body.SetAttributeValue("flavor", cty.StringVal(component.Body.Flavor))
+		if component.Metadata == nil {
+			continue
+		}
+
+		configuration := exportConfiguration(component.Metadata.Configuration)
+		secretKeys, known := sensitiveKeys[component.Body.Type+"/"+component.Body.Flavor]
+		if !known {
+			// Without a schema nothing tells secret values apart.
+			if len(configuration) > 0 {
+				appendComment(body, fmt.Sprintf("The configuration schema of flavor %s is unknown, so the\n"+
+					"configuration is not exported. Add it to `configuration` and credentials\n"+
+					"to `secret_configuration` before applying changes to this component.",
+					component.Body.Flavor))
+			}
+			configuration = nil
+		}
+		var omitted []string
+		for key, value := range configuration {
+			if secretKeys[key] && !isSecretReference(value) {
+				omitted = append(omitted, key)
+				delete(configuration, key)
+			}
+		}
+		setStringMapAttribute(body, "configuration", configuration)
+		if len(omitted) > 0 {
+			sort.Strings(omitted)
+			appendComment(body, fmt.Sprintf("Secret configuration values are not exported: %s.\n"+
+				"Add them to `secret_configuration` before applying changes to this component.",
+				strings.Join(omitted, ", ")))
+		}
+		if component.Metadata.Connector != nil {
+			if ref, ok := e.connectors[component.Metadata.Connector.ID]; ok {
+				body.SetAttributeTraversal("connector_id", withAttr(ref, "id"))
+			} else {
+				body.SetAttributeValue("connector_id", cty.StringVal(component.Metadata.Connector.ID))
+			}
+			if component.Metadata.ConnectorResourceID != nil {
+				body.SetAttributeValue("connector_resource_id", cty.StringVal(*component.Metadata.ConnectorResourceID))
+			}
+		}
+		setStringMapAttribute(body, "labels", component.Metadata.Labels)
+	}
+	return nil
+}
+
+func (e *exporter) exportStacks(ctx context.Context) error {
+	items, err := collectPages(ctx, &ListParams{}, e.client.ListStacks)
+	if err != nil {
+		return fmt.Errorf("error listing stacks: %w", err)
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].Name < items[j].Name })
+
+	for _, item := range items {
+		stack, err := e.client.GetStack(ctx, item.ID)
+		if err != nil {
+			return fmt.Errorf("error reading stack %s: %w", item.Name, err)
+		}
+		if stack == nil {
+			continue
+		}
+
+		body, address := e.addResource("zenml_stack", stack.Name, stack.ID)
+		e.stacks[stack.ID] = address
+		body.SetAttributeValue("name", cty.StringVal(stack.Name))
+		if stack.Metadata == nil {
+			continue
+		}
+
+		componentTypes := make([]string, 0, len(stack.Metadata.Components))
+		for componentType, components := range stack.Metadata.Components {
+			if len(components) > 0 {
+				componentTypes = append(componentTypes, componentType)
+			}
+		}
+		sort.Strings(componentTypes)
+
+		componentItems := make([]hclwrite.ObjectAttrTokens, 0, len(componentTypes))
+		for _, componentType := range componentTypes {
+			id := stack.Metadata.Components[componentType][0].ID
+			componentItems = append(componentItems, hclwrite.ObjectAttrTokens{
+				Name:  hclwrite.TokensForIdentifier(componentType),
+				Value: e.reference(e.components, id),
+			})
+		}
+		body.SetAttributeRaw("components", hclwrite.TokensForObject(componentItems))
+
+		setStringMapAttribute(body, "labels", stack.Metadata.Labels)
+		setStringMapAttribute(body, "environment", stack.Metadata.Environment)
+
+		if len(stack.Metadata.Secrets) > 0 {
+			secretItems := make([]hclwrite.Tokens, 0, len(stack.Metadata.Secrets))
+			for _, id := range stack.Metadata.Secrets {
+				secretItems = append(secretItems, e.reference(e.secrets, id))
+			}
+			body.SetAttributeRaw("secrets", hclwrite.TokensForTuple(secretItems))
+		}
+	}
+	return nil
+}
+
+func (e *exporter) exportProjects(ctx context.Context) error {
+	items, err := collectPages(ctx, &ListParams{}, e.client.ListProjects)
+	if err != nil {
+		return fmt.Errorf("error listing projects: %w", err)
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].Name < items[j].Name })
+
+	for _, item := range items {
+		project, err := e.client.GetProject(ctx, item.ID)
+		if err != nil {
+			return fmt.Errorf("error reading project %s: %w", item.Name, err)
+		}
+		if project == nil {
+			continue
+		}
+
+		body, address := e.addResource("zenml_project", project.Name, project.ID)
+		body.SetAttributeValue("name", cty.StringVal(project.Name))
+		if project.Body != nil && project.Body.DisplayName != "" {
+			body.SetAttributeValue("display_name", cty.StringVal(project.Body.DisplayName))
+		}
+		if project.Metadata != nil && project.Metadata.Description != "" {
+			body.SetAttributeValue("description", cty.StringVal(project.Metadata.Description))
+		}
+
+		if project.Body == nil || project.Body.DefaultStackID == nil {
+			continue
+		}
+		defaultStack, _ := e.addResource("zenml_project_default_stack", project.Name, project.ID)
+		defaultStack.SetAttributeTraversal("project", withAttr(address, "id"))
+		defaultStack.SetAttributeRaw("stack_id", e.reference(e.stacks, *project.Body.DefaultStackID))
+	}
+	return nil
+}
+
+// reference returns a reference to the id of the exported object with the
+// given ID, or the ID itself if the object was not exported.
+func (e *exporter) reference(addresses map[string]hcl.Traversal, id string) hclwrite.Tokens {
+	if address, ok := addresses[id]; ok {
+		return hclwrite.TokensForTraversal(withAttr(address, "id"))
+	}
+	return hclwrite.TokensForValue(cty.StringVal(id))
+}
+
+func withAttr(address hcl.Traversal, name string) hcl.Traversal {
+	traversal := make(hcl.Traversal, 0, len(address)+1)
+	traversal = append(traversal, address...)
+	return append(traversal, hcl.TraverseAttr{Name: name})
+}
+
+func setStringMapAttribute(body *hclwrite.Body, name string, values map[string]string) {
+	if len(values) == 0 {
+		return
+	}
+	ctyValues := make(map[string]cty.Value, len(values))
+	for k, v := range values {
+		ctyValues[k] = cty.StringVal(v)
+	}
+	body.SetAttributeValue(name, cty.MapVal(ctyValues))
+}
+
+func appendComment(body *hclwrite.Body, comment string) {
+	for _, line := range strings.Split(comment, "\n") {
+		body.AppendUnstructuredTokens(hclwrite.Tokens{
+			{Type: hclsyntax.TokenComment, Bytes: []byte("# " + line + "\n")},
+		})
+	}
+}
+
+// exportConfiguration converts a server configuration to the string map used
+// by the configuration attributes, leaving out unset values.
+func exportConfiguration(raw map[string]interface{}) map[string]string {
+	set := make(map[string]interface{}, len(raw))
+	for k, v := range raw {
+		if v != nil {
+			set[k] = v
+		}
+	}
+	return NormalizeServerConfig(set)
+}
+
+// secretReferencePattern matches values that refer to a ZenML secret, such as
+// {{aws_credentials.secret_key}}, instead of holding the secret.
+var secretReferencePattern = regexp.MustCompile(`^\{\{\s*[^{}.\s]+\.[^{}\s]+\s*\}\}$`)
+
+func isSecretReference(value string) bool {
+	return secretReferencePattern.MatchString(value)
+}
+
+// connectorTypeName extracts the connector type from a connector response,
+// which holds either the type name or the full connector type.
+func connectorTypeName(raw json.RawMessage) string {
+	var connectorType string
+	if err := json.Unmarshal(raw, &connectorType); err == nil {
+		return connectorType
+	}
+	var connectorTypeObj struct {
+		ConnectorType string `json:"connector_type"`
+	}
+	if err := json.Unmarshal(raw, &connectorTypeObj); err == nil {
+		return connectorTypeObj.ConnectorType
+	}
+	return ""
+}
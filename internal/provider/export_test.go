@@ -0,0 +1,184 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+)
+
+func testExportServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	connector := map[string]interface{}{
+		"id":   "connector-1",
+		"name": "gcp-connector",
+		"body": map[string]interface{}{
+			"connector_type": "gcp",
+			"auth_method":    "service-account",
+			"resource_types": []string{"gcs-bucket"},
+		},
+		"metadata": map[string]interface{}{
+			"configuration": map[string]interface{}{"project_id": "my-project"},
+			"secret_id":     "internal-secret",
+		},
+	}
+	component := map[string]interface{}{
+		"id":   "component-1",
+		"name": "gcs-store",
+		"body": map[string]interface{}{"type": "artifact_store", "flavor_name": "gcp"},
+		"metadata": map[string]interface{}{
+			"configuration": map[string]interface{}{
+				"path":     "gs://bucket",
+				"unset":    nil,
+				"token":    "plain-token",
+				"key_file": "{{gcp_credentials.key_file}}",
+			},
+			"connector":             map[string]interface{}{"id": "connector-1", "name": "gcp-connector"},
+			"connector_resource_id": "gs://bucket",
+		},
+	}
+	orchestrator := map[string]interface{}{
+		"id":   "component-2",
+		"name": "default",
+		"body": map[string]interface{}{"type": "orchestrator", "flavor_name": "local"},
+	}
+	stack := map[string]interface{}{
+		"id":   "stack-1",
+		"name": "production",
+		"metadata": map[string]interface{}{
+			"components": map[string]interface{}{
+				"artifact_store": []interface{}{component},
+				"orchestrator":   []interface{}{orchestrator},
+			},
+			"labels":  map[string]string{"env": "prod"},
+			"secrets": []string{"secret-1"},
+		},
+	}
+	secret := map[string]interface{}{
+		"id":   "secret-1",
+		"name": "db-credentials",
+		"body": map[string]interface{}{"private": true},
+	}
+	project := map[string]interface{}{
+		"id":   "project-1",
+		"name": "default",
+		"body": map[string]interface{}{"display_name": "Default", "default_stack_id": "stack-1"},
+	}
+
+	flavor := map[string]interface{}{
+		"name": "gcp",
+		"body": map[string]interface{}{"type": "artifact_store"},
+		"metadata": map[string]interface{}{
+			"config_schema": map[string]interface{}{
+				"properties": map[string]interface{}{
+					"path":     map[string]interface{}{"type": "string"},
+					"token":    map[string]interface{}{"type": "string", "sensitive": true},
+					"key_file": map[string]interface{}{"type": "string", "format": "password"},
+				},
+			},
+		},
+	}
+
+	page := func(items ...interface{}) interface{} {
+		return map[string]interface{}{"index": 1, "total_pages": 1, "total": len(items), "items": items}
+	}
+	responses := map[string]interface{}{
+		"/api/v1/service_connectors":             page(connector),
+		"/api/v1/service_connectors/connector-1": connector,
+		"/api/v1/components":                     page(component, orchestrator),
+		"/api/v1/components/component-1":         component,
+		"/api/v1/components/component-2":         orchestrator,
+		"/api/v1/stacks":                         page(stack),
+		"/api/v1/stacks/stack-1":                 stack,
+		"/api/v1/secrets":                        page(secret),
+		"/api/v1/projects":                       page(project),
+		"/api/v1/projects/project-1":             project,
+		"/api/v1/flavors":                        page(flavor),
+	}
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		response, ok := responses[r.URL.Path]
+		if r.URL.Path == "/api/v1/flavors" && r.URL.Query().Get("hydrate") != "true" {
+			// Configuration schemas are only part of hydrated flavors.
+			ok = false
+		}
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		if err := json.NewEncoder(w).Encode(response); err != nil {
+			t.Errorf("unexpected error encoding response: %s", err)
+		}
+	}))
+}
+
+func TestExport(t *testing.T) {
+	server := testExportServer(t)
+	defer server.Close()
+
+	var out bytes.Buffer
+	client := NewClient(server.URL, "", "test-token")
+	if err := Export(context.Background(), client, &out); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	config := out.String()
+
+	if _, diags := hclsyntax.ParseConfig(out.Bytes(), "export.tf", hcl.InitialPos); diags.HasErrors() {
+		t.Fatalf("exported configuration does not parse: %s\n%s", diags, config)
+	}
+
+	for _, want := range []string{
+		`to = zenml_service_connector.gcp_connector`,
+		`id = "connector-1"`,
+		`connector_id          = zenml_service_connector.gcp_connector.id`,
+		`artifact_store = zenml_stack_component.artifact_store_gcs_store.id`,
+		`orchestrator   = zenml_stack_component.orchestrator_default.id`,
+		`secrets = [zenml_secret.db_credentials.id]`,
+		`ignore_changes = [values]`,
+		`project  = zenml_project.default.id`,
+		`stack_id = zenml_stack.production.id`,
+		`# Secret configuration values are not exported.`,
+		`# Secret configuration values are not exported: token.`,
+		`key_file = "{{gcp_credentials.key_file}}"`,
+	} {
+		if !strings.Contains(config, want) {
+			t.Errorf("expected exported configuration to contain %q, got:\n%s", want, config)
+		}
+	}
+
+	if strings.Contains(config, "plain-token") {
+		t.Errorf("expected secret configuration values to be left out, got:\n%s", config)
+	}
+	if strings.Contains(config, "unset") {
+		t.Errorf("expected unset configuration values to be left out, got:\n%s", config)
+	}
+}
+
+func TestExportNames(t *testing.T) {
+	names := exportNames{}
+
+	cases := []struct {
+		resourceType string
+		objectName   string
+		want         string
+	}{
+		{"zenml_stack", "My Stack", "my_stack"},
+		{"zenml_stack", "my-stack", "my_stack_2"},
+		{"zenml_secret", "my-stack", "my_stack"},
+		{"zenml_stack", "1st", "_1st"},
+		{"zenml_stack", "---", "unnamed"},
+	}
+
+	for _, tc := range cases {
+		if got := names.name(tc.resourceType, tc.objectName); got != tc.want {
+			t.Errorf("name(%q, %q) = %q, want %q", tc.resourceType, tc.objectName, got, tc.want)
+		}
+	}
+}
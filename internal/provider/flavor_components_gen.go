@@ -0,0 +1,220 @@
+// Code generated by tools/flavorgen from flavor_schemas.json. DO NOT EDIT.
+
+package provider
+
+var flavorComponentSpecs = []flavorComponentSpec{
+	{
+		TypeName:      "artifact_store_gcp",
+		ComponentType: "artifact_store",
+		Flavor:        "gcp",
+		Description:   "Manages a ZenML artifact store stack component of the `gcp` flavor.",
+		Attributes: []flavorAttributeSpec{
+			{
+				Name:        "path",
+				Key:         "path",
+				Kind:        flavorAttributeString,
+				Description: "Path to the artifact store.",
+				Required:    true,
+			},
+		},
+	},
+	{
+		TypeName:      "artifact_store_local",
+		ComponentType: "artifact_store",
+		Flavor:        "local",
+		Description:   "Manages a ZenML artifact store stack component of the `local` flavor.",
+		Attributes: []flavorAttributeSpec{
+			{
+				Name:        "path",
+				Key:         "path",
+				Kind:        flavorAttributeString,
+				Description: "Path to the local directory that holds the artifacts. Defaults to a directory in the local ZenML config directory.",
+			},
+		},
+	},
+	{
+		TypeName:      "artifact_store_s3",
+		ComponentType: "artifact_store",
+		Flavor:        "s3",
+		Description:   "Manages a ZenML artifact store stack component of the `s3` flavor.",
+		Attributes: []flavorAttributeSpec{
+			{
+				Name:        "client_kwargs",
+				Key:         "client_kwargs",
+				Kind:        flavorAttributeJSON,
+				Description: "Additional keyword arguments for the S3 client, for example endpoint_url and region_name.",
+			},
+			{
+				Name:        "config_kwargs",
+				Key:         "config_kwargs",
+				Kind:        flavorAttributeJSON,
+				Description: "Additional keyword arguments for the botocore configuration.",
+			},
+			{
+				Name:        "key",
+				Key:         "key",
+				Kind:        flavorAttributeString,
+				Description: "Key",
+				Sensitive:   true,
+			},
+			{
+				Name:        "path",
+				Key:         "path",
+				Kind:        flavorAttributeString,
+				Description: "Path to the S3 bucket, for example s3://my-bucket/artifacts.",
+				Required:    true,
+			},
+			{
+				Name:        "s3_additional_kwargs",
+				Key:         "s3_additional_kwargs",
+				Kind:        flavorAttributeJSON,
+				Description: "Additional keyword arguments for S3 API calls.",
+			},
+			{
+				Name:        "secret",
+				Key:         "secret",
+				Kind:        flavorAttributeString,
+				Description: "Secret",
+				Sensitive:   true,
+			},
+			{
+				Name:        "token",
+				Key:         "token",
+				Kind:        flavorAttributeString,
+				Description: "Token",
+				Sensitive:   true,
+			},
+		},
+	},
+	{
+		TypeName:      "container_registry_aws",
+		ComponentType: "container_registry",
+		Flavor:        "aws",
+		Description:   "Manages a ZenML container registry stack component of the `aws` flavor.",
+		Attributes: []flavorAttributeSpec{
+			{
+				Name:        "default_repository",
+				Key:         "default_repository",
+				Kind:        flavorAttributeString,
+				Description: "Default repository to push images to. If not set, the repository name is derived from the image name.",
+			},
+			{
+				Name:        "uri",
+				Key:         "uri",
+				Kind:        flavorAttributeString,
+				Description: "URI of the ECR registry, for example 123456789012.dkr.ecr.us-east-1.amazonaws.com.",
+				Required:    true,
+			},
+		},
+	},
+	{
+		TypeName:      "container_registry_gcp",
+		ComponentType: "container_registry",
+		Flavor:        "gcp",
+		Description:   "Manages a ZenML container registry stack component of the `gcp` flavor.",
+		Attributes: []flavorAttributeSpec{
+			{
+				Name:        "default_repository",
+				Key:         "default_repository",
+				Kind:        flavorAttributeString,
+				Description: "Default repository to push images to.",
+			},
+			{
+				Name:        "uri",
+				Key:         "uri",
+				Kind:        flavorAttributeString,
+				Description: "URI of the Artifact Registry repository, for example europe-west1-docker.pkg.dev/my-project/my-repository.",
+				Required:    true,
+			},
+		},
+	},
+	{
+		TypeName:      "orchestrator_kubernetes",
+		ComponentType: "orchestrator",
+		Flavor:        "kubernetes",
+		Description:   "Manages a ZenML orchestrator stack component of the `kubernetes` flavor.",
+		Attributes: []flavorAttributeSpec{
+			{
+				Name:        "image_pull_policy",
+				Key:         "image_pull_policy",
+				Kind:        flavorAttributeString,
+				Description: "Image pull policy of the pipeline pods.",
+				Default:     "\"IfNotPresent\"",
+				Enum:        []string{"Always", "IfNotPresent", "Never"},
+			},
+			{
+				Name:        "incluster",
+				Key:         "incluster",
+				Kind:        flavorAttributeBool,
+				Description: "Whether to run the pipelines in the cluster the client runs in, using the in-cluster Kubernetes configuration.",
+				Default:     "false",
+			},
+			{
+				Name:        "kubernetes_context",
+				Key:         "kubernetes_context",
+				Kind:        flavorAttributeString,
+				Description: "Name of the Kubernetes context to use. Ignored when a service connector is linked.",
+			},
+			{
+				Name:        "kubernetes_namespace",
+				Key:         "kubernetes_namespace",
+				Kind:        flavorAttributeString,
+				Description: "Kubernetes namespace to run the pipeline pods in.",
+				Default:     "\"zenml\"",
+			},
+			{
+				Name:        "local",
+				Key:         "local",
+				Kind:        flavorAttributeBool,
+				Description: "Whether the orchestrator runs against a local Kubernetes cluster.",
+				Default:     "false",
+			},
+			{
+				Name:        "max_parallelism",
+				Key:         "max_parallelism",
+				Kind:        flavorAttributeInt64,
+				Description: "Maximum number of steps to run in parallel.",
+			},
+			{
+				Name:        "pod_settings",
+				Key:         "pod_settings",
+				Kind:        flavorAttributeJSON,
+				Description: "Default pod settings of the pipeline pods.",
+			},
+			{
+				Name:        "service_account_name",
+				Key:         "service_account_name",
+				Kind:        flavorAttributeString,
+				Description: "Name of the service account the pipeline pods run as.",
+			},
+			{
+				Name:        "skip_local_validations",
+				Key:         "skip_local_validations",
+				Kind:        flavorAttributeBool,
+				Description: "Whether to skip the validation of the local Kubernetes context.",
+				Default:     "false",
+			},
+			{
+				Name:        "synchronous",
+				Key:         "synchronous",
+				Kind:        flavorAttributeBool,
+				Description: "Whether the client waits for the pipeline run to finish.",
+				Default:     "true",
+			},
+			{
+				Name:        "timeout",
+				Key:         "timeout",
+				Kind:        flavorAttributeInt64,
+				Description: "Seconds to wait for a synchronous pipeline run to finish. 0 waits indefinitely.",
+				Default:     "0",
+			},
+		},
+	},
+	{
+		TypeName:      "orchestrator_local",
+		ComponentType: "orchestrator",
+		Flavor:        "local",
+		Description:   "Manages a ZenML orchestrator stack component of the `local` flavor.",
+		Attributes:    []flavorAttributeSpec{},
+	},
+}
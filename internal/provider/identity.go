@@ -0,0 +1,161 @@
+// identity.go
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/identityschema"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// ResourceIdentityModel is the identity shared by all ZenML resources. Stacks,
+// stack components, service connectors, secrets and projects are unique per
+// ZenML server, so the server URL and the ID identify them unambiguously. The
+// project is part of the identity so that project-scoped resources can share
+// it, but it is always null for the server-wide resources implemented today.
+type ResourceIdentityModel struct {
+	ServerURL types.String `tfsdk:"server_url"`
+	Project   types.String `tfsdk:"project"`
+	ID        types.String `tfsdk:"id"`
+}
+
+func resourceIdentitySchema(kind string) identityschema.Schema {
+	return identityschema.Schema{
+		Attributes: map[string]identityschema.Attribute{
+			"server_url": identityschema.StringAttribute{
+				OptionalForImport: true,
+				Description: "URL of the ZenML server the " + kind + " belongs " +
+					"to. Defaults to the server configured for the provider.",
+			},
+			"project": identityschema.StringAttribute{
+				OptionalForImport: true,
+				Description: "Project the " + kind + " belongs to. Always " +
+					"null, as " + kind + "s are not scoped to a project.",
+			},
+			"id": identityschema.StringAttribute{
+				RequiredForImport: true,
+				Description:       "ID of the " + kind,
+			},
+		},
+	}
+}
+
+// normalizeServerURL strips trailing slashes so that URLs that only differ in
+// them compare equal.
+func normalizeServerURL(serverURL string) string {
+	return strings.TrimRight(serverURL, "/")
+}
+
+// setResourceIdentity records the identity of the resource with the given ID.
+func setResourceIdentity(
+	ctx context.Context,
+	client *Client,
+	id string,
+	identity *tfsdk.ResourceIdentity,
+	diags *diag.Diagnostics,
+) {
+	if identity == nil {
+		return
+	}
+
+	diags.Append(identity.Set(ctx, ResourceIdentityModel{
+		ServerURL: types.StringValue(normalizeServerURL(client.ServerURL)),
+		Project:   types.StringNull(),
+		ID:        types.StringValue(id),
+	})...)
+}
+
+// checkResourceIdentity verifies that a resource identity refers to the ZenML
+// server configured for the provider and, when id is not empty, to the
+// resource with that ID. Resources that were created against a different
+// server cannot be managed through this provider configuration.
+func checkResourceIdentity(
+	ctx context.Context,
+	client *Client,
+	kind string,
+	id string,
+	identity *tfsdk.ResourceIdentity,
+	diags *diag.Diagnostics,
+) {
+	if identity == nil || identity.Raw.IsNull() {
+		return
+	}
+
+	var data ResourceIdentityModel
+	diags.Append(identity.Get(ctx, &data)...)
+	if diags.HasError() {
+		return
+	}
+
+	if id != "" && !data.ID.IsNull() && data.ID.ValueString() != id {
+		diags.AddAttributeError(
+			path.Root("id"),
+			"Resource Identity Mismatch",
+			fmt.Sprintf(
+				"The resource identity refers to %s %s, but the state refers "+
+					"to %s %s.",
+				kind, data.ID.ValueString(), kind, id,
+			),
+		)
+	}
+
+	if !data.Project.IsNull() && data.Project.ValueString() != "" {
+		diags.AddAttributeWarning(
+			path.Root("project"),
+			"Resource Identity Project Ignored",
+			fmt.Sprintf(
+				"%ss are not scoped to a project, so the project %q in the "+
+					"resource identity is ignored.",
+				kind, data.Project.ValueString(),
+			),
+		)
+	}
+
+	if data.ServerURL.IsNull() || data.ServerURL.ValueString() == "" {
+		return
+	}
+
+	serverURL := normalizeServerURL(data.ServerURL.ValueString())
+	if serverURL != normalizeServerURL(client.ServerURL) {
+		diags.AddAttributeError(
+			path.Root("server_url"),
+			"Resource Identity Mismatch",
+			fmt.Sprintf(
+				"The %s %s belongs to the ZenML server at %s, but the provider "+
+					"is configured for %s. Configure the provider for the "+
+					"server the %s belongs to.",
+				kind, data.ID.ValueString(), serverURL,
+				normalizeServerURL(client.ServerURL), kind,
+			),
+		)
+	}
+}
+
+// importStateFromIdentity imports a resource from the identity attribute of an
+// import block. It reports whether the import was handled, so that callers can
+// fall back to importing by import ID otherwise.
+func importStateFromIdentity(
+	ctx context.Context,
+	client *Client,
+	kind string,
+	req resource.ImportStateRequest,
+	resp *resource.ImportStateResponse,
+) bool {
+	if req.ID != "" || req.Identity == nil || req.Identity.Raw.IsNull() {
+		return false
+	}
+
+	checkResourceIdentity(ctx, client, kind, "", req.Identity, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return true
+	}
+
+	resource.ImportStatePassthroughWithIdentity(ctx, path.Root("id"), path.Root("id"), req, resp)
+	return true
+}
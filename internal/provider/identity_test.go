@@ -0,0 +1,89 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func testResourceIdentity(t *testing.T, serverURL, project, id interface{}) *tfsdk.ResourceIdentity {
+	t.Helper()
+	ctx := context.Background()
+
+	schemaResp := &resource.IdentitySchemaResponse{}
+	(&StackResource{}).IdentitySchema(ctx, resource.IdentitySchemaRequest{}, schemaResp)
+	if schemaResp.Diagnostics.HasError() {
+		t.Fatalf("unexpected identity schema diagnostics: %v", schemaResp.Diagnostics)
+	}
+
+	objectType := schemaResp.IdentitySchema.Type().TerraformType(ctx).(tftypes.Object)
+	return &tfsdk.ResourceIdentity{
+		Schema: schemaResp.IdentitySchema,
+		Raw: tftypes.NewValue(objectType, map[string]tftypes.Value{
+			"server_url": tftypes.NewValue(tftypes.String, serverURL),
+			"project":    tftypes.NewValue(tftypes.String, project),
+			"id":         tftypes.NewValue(tftypes.String, id),
+		}),
+	}
+}
+
+func TestSetResourceIdentity(t *testing.T) {
+	ctx := context.Background()
+	client := NewClient("https://zenml.example.com/", "", "")
+	identity := testResourceIdentity(t, nil, nil, nil)
+
+	var diags diag.Diagnostics
+	setResourceIdentity(ctx, client, "stack-1", identity, &diags)
+	if diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+
+	var got ResourceIdentityModel
+	if d := identity.Get(ctx, &got); d.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", d)
+	}
+	if got.ServerURL.ValueString() != "https://zenml.example.com" {
+		t.Errorf("expected normalized server URL, got %q", got.ServerURL.ValueString())
+	}
+	if !got.Project.IsNull() {
+		t.Errorf("expected null project, got %q", got.Project.ValueString())
+	}
+	if got.ID.ValueString() != "stack-1" {
+		t.Errorf("expected ID stack-1, got %q", got.ID.ValueString())
+	}
+}
+
+func TestCheckResourceIdentity(t *testing.T) {
+	ctx := context.Background()
+	client := NewClient("https://zenml.example.com", "", "")
+
+	cases := []struct {
+		name      string
+		identity  *tfsdk.ResourceIdentity
+		id        string
+		wantError bool
+		wantWarn  bool
+	}{
+		{"matching", testResourceIdentity(t, "https://zenml.example.com/", nil, "stack-1"), "stack-1", false, false},
+		{"no server URL", testResourceIdentity(t, nil, nil, "stack-1"), "stack-1", false, false},
+		{"other server", testResourceIdentity(t, "https://other.example.com", nil, "stack-1"), "stack-1", true, false},
+		{"other ID", testResourceIdentity(t, nil, nil, "stack-2"), "stack-1", true, false},
+		{"project ignored", testResourceIdentity(t, nil, "default", "stack-1"), "stack-1", false, true},
+		{"no identity", nil, "stack-1", false, false},
+	}
+
+	for _, tc := range cases {
+		var diags diag.Diagnostics
+		checkResourceIdentity(ctx, client, "stack", tc.id, tc.identity, &diags)
+		if diags.HasError() != tc.wantError {
+			t.Errorf("%s: expected error=%v, got %v", tc.name, tc.wantError, diags)
+		}
+		if (diags.WarningsCount() > 0) != tc.wantWarn {
+			t.Errorf("%s: expected warning=%v, got %v", tc.name, tc.wantWarn, diags)
+		}
+	}
+}
@@ -0,0 +1,114 @@
+// import.go
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+)
+
+// importStateByNameOrID imports a resource using either its UUID or a
+// human-readable identifier. Non-UUID import IDs are resolved to the UUID of
+// the single object matched by the given list filter.
+func importStateByNameOrID[T any](
+	ctx context.Context,
+	req resource.ImportStateRequest,
+	resp *resource.ImportStateResponse,
+	kind string,
+	filter map[string]string,
+	list func(context.Context, *ListParams) (*Page[T], error),
+	idOf func(T) string,
+) {
+	if isUUID(req.ID) {
+		resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+		return
+	}
+
+	items, err := collectPages(ctx, &ListParams{Filter: filter}, list)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Client Error",
+			fmt.Sprintf("Unable to look up %s %q for import, got error: %s", kind, req.ID, err),
+		)
+		return
+	}
+
+	id, err := singleImportMatch(kind, req.ID, items, idOf)
+	if err != nil {
+		resp.Diagnostics.AddError("Import Error", err.Error())
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), id)...)
+}
+
+// importedPrivateKey is the private state key that marks resources which were
+// imported and not read since.
+const importedPrivateKey = "imported"
+
+// markImported records that a resource was imported, so that the read that
+// follows the import adopts its complete configuration.
+func markImported(ctx context.Context, resp *resource.ImportStateResponse) {
+	if resp.Private != nil {
+		resp.Diagnostics.Append(resp.Private.SetKey(ctx, importedPrivateKey, []byte("true"))...)
+	}
+}
+
+// readConfigurationRefresh returns how a read reconciles configuration with
+// the server. The first read after an import adopts all configuration keys of
+// the server, later reads only track the keys in state.
+func readConfigurationRefresh(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) configurationRefresh {
+	if req.Private == nil {
+		return configurationTrack
+	}
+
+	imported, diags := req.Private.GetKey(ctx, importedPrivateKey)
+	resp.Diagnostics.Append(diags...)
+	if len(imported) == 0 {
+		return configurationTrack
+	}
+
+	if resp.Private != nil {
+		resp.Diagnostics.Append(resp.Private.SetKey(ctx, importedPrivateKey, nil)...)
+	}
+	return configurationAdopt
+}
+
+// singleImportMatch returns the ID of the only item matched by an import ID,
+// or an error describing why the import ID is not unambiguous.
+func singleImportMatch[T any](kind, importID string, items []T, idOf func(T) string) (string, error) {
+	switch len(items) {
+	case 0:
+		return "", fmt.Errorf("no %s found matching %q", kind, importID)
+	case 1:
+		return idOf(items[0]), nil
+	}
+
+	ids := make([]string, len(items))
+	for i, item := range items {
+		ids[i] = idOf(item)
+	}
+	return "", fmt.Errorf(
+		"%d %ss match %q: %s. Import one of them by ID instead",
+		len(items), kind, importID, strings.Join(ids, ", "),
+	)
+}
+
+// parseComponentImportID splits a stack component import ID of the form
+// "<type>/<name>" into its parts. IDs without a valid component type prefix
+// are treated as a plain name.
+func parseComponentImportID(importID string) (string, string) {
+	componentType, name, found := strings.Cut(importID, "/")
+	if !found {
+		return "", importID
+	}
+	for _, validType := range validComponentTypes {
+		if componentType == validType {
+			return componentType, name
+		}
+	}
+	return "", importID
+}
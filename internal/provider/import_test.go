@@ -0,0 +1,55 @@
+package provider
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseComponentImportID(t *testing.T) {
+	cases := []struct {
+		importID string
+		wantType string
+		wantName string
+	}{
+		{"artifact_store/s3-store", "artifact_store", "s3-store"},
+		{"s3-store", "", "s3-store"},
+		{"team/s3-store", "", "team/s3-store"},
+	}
+
+	for _, tc := range cases {
+		gotType, gotName := parseComponentImportID(tc.importID)
+		if gotType != tc.wantType || gotName != tc.wantName {
+			t.Errorf(
+				"parseComponentImportID(%q) = (%q, %q), want (%q, %q)",
+				tc.importID, gotType, gotName, tc.wantType, tc.wantName,
+			)
+		}
+	}
+}
+
+func TestSingleImportMatch(t *testing.T) {
+	idOf := func(s StackResponse) string { return s.ID }
+
+	if _, err := singleImportMatch("stack", "prod", []StackResponse{}, idOf); err == nil {
+		t.Error("expected an error when no stack matches")
+	}
+
+	id, err := singleImportMatch("stack", "prod", []StackResponse{{ID: "stack-1"}}, idOf)
+	if err != nil || id != "stack-1" {
+		t.Errorf("expected stack-1, got %q (err=%v)", id, err)
+	}
+
+	_, err = singleImportMatch("stack", "prod", []StackResponse{{ID: "stack-1"}, {ID: "stack-2"}}, idOf)
+	if err == nil || !strings.Contains(err.Error(), "stack-1, stack-2") {
+		t.Errorf("expected ambiguity error listing both stacks, got %v", err)
+	}
+}
+
+func TestIsUUID(t *testing.T) {
+	if !isUUID("12345678-1234-1234-1234-123456789012") {
+		t.Error("expected a dashed UUID to be recognized")
+	}
+	if isUUID("production-stack") {
+		t.Error("expected a name not to be recognized as a UUID")
+	}
+}
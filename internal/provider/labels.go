@@ -0,0 +1,296 @@
+// labels.go
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+const (
+	// managedByLabel is set on every labelled object the provider manages,
+	// so that they can be told apart from objects created by other means.
+	managedByLabel = "managed-by"
+	managedByValue = "terraform"
+	// workspaceLabel identifies the Terraform workspace that manages an
+	// object, if the provider knows it.
+	workspaceLabel = "terraform-workspace"
+)
+
+// Values of labels_mode. In authoritative mode, the default, a resource owns
+// all labels of its object. In additive mode, it only manages the keys it
+// declares and keeps the labels that others add.
+const (
+	labelsModeAuthoritative = "authoritative"
+	labelsModeAdditive      = "additive"
+)
+
+// providerLabels returns the labels the provider adds to every labelled
+// object: the managed-by labels and the configured default labels, which can
+// override them. The workspace defaults to the TF_WORKSPACE and
+// TFC_WORKSPACE_NAME environment variables.
+func providerLabels(defaultLabels map[string]string, workspace string) map[string]string {
+	if workspace == "" {
+		workspace = os.Getenv("TF_WORKSPACE")
+	}
+	if workspace == "" {
+		workspace = os.Getenv("TFC_WORKSPACE_NAME")
+	}
+
+	labels := map[string]string{managedByLabel: managedByValue}
+	if workspace != "" {
+		labels[workspaceLabel] = workspace
+	}
+	for k, v := range defaultLabels {
+		labels[k] = v
+	}
+	return labels
+}
+
+func labelsAllAttribute() schema.MapAttribute {
+	return schema.MapAttribute{
+		MarkdownDescription: "All labels of the object, including the labels the " +
+			"provider adds through `default_labels` and the managed-by labels.",
+		ElementType: types.StringType,
+		Computed:    true,
+	}
+}
+
+func labelsModeAttribute() schema.StringAttribute {
+	return schema.StringAttribute{
+		MarkdownDescription: "How the resource manages the labels of its object. In `authoritative` " +
+			"mode, the default, labels that are not configured are removed. In `additive` mode, only " +
+			"the configured keys and the provider labels are managed, and labels added by others are kept.",
+		Optional: true,
+		Validators: []validator.String{
+			stringvalidator.OneOf(labelsModeAuthoritative, labelsModeAdditive),
+		},
+	}
+}
+
+func isAdditiveLabels(mode types.String) bool {
+	return mode.ValueString() == labelsModeAdditive
+}
+
+// additiveLabels returns the labels to send to the server in additive mode:
+// the current labels of the object, without the keys the resource managed
+// before, and with the desired labels on top. The resource managed the keys of
+// its previous labels and the provider labels it applied that the object
+// still has, so that keys removed from default_labels are removed as well.
+func additiveLabels(current map[string]string, previous types.Map, applied map[string]string, desired map[string]string) map[string]string {
+	labels := make(map[string]string, len(current)+len(desired))
+	for k, v := range current {
+		if _, managed := previous.Elements()[k]; managed {
+			continue
+		}
+		if value, ok := applied[k]; ok && value == v {
+			continue
+		}
+		labels[k] = v
+	}
+	for k, v := range desired {
+		labels[k] = v
+	}
+	return labels
+}
+
+// providerLabelsPrivateKey is the private state key holding the provider
+// labels last applied to an object.
+const providerLabelsPrivateKey = "provider_labels"
+
+type privateKeyGetter interface {
+	GetKey(ctx context.Context, key string) ([]byte, diag.Diagnostics)
+}
+
+type privateKeySetter interface {
+	SetKey(ctx context.Context, key string, value []byte) diag.Diagnostics
+}
+
+// appliedProviderLabels returns the provider labels last applied to an
+// object, or nil for objects created by earlier versions of the provider.
+func appliedProviderLabels(ctx context.Context, private privateKeyGetter, diags *diag.Diagnostics) map[string]string {
+	value, getDiags := private.GetKey(ctx, providerLabelsPrivateKey)
+	diags.Append(getDiags...)
+	if len(value) == 0 {
+		return nil
+	}
+
+	var labels map[string]string
+	if err := json.Unmarshal(value, &labels); err != nil {
+		diags.AddError("Invalid Private State", fmt.Sprintf("Unable to read the applied provider labels, got error: %s", err))
+		return nil
+	}
+	return labels
+}
+
+// recordProviderLabels records the provider labels applied to an object, see
+// appliedProviderLabels.
+func recordProviderLabels(ctx context.Context, private privateKeySetter, client *Client, diags *diag.Diagnostics) {
+	var labels map[string]string
+	if client != nil {
+		labels = client.DefaultLabels
+	}
+
+	value, err := json.Marshal(labels)
+	if err != nil {
+		diags.AddError("Invalid Private State", fmt.Sprintf("Unable to record the applied provider labels, got error: %s", err))
+		return
+	}
+	diags.Append(private.SetKey(ctx, providerLabelsPrivateKey, value)...)
+}
+
+// mergeLabels returns the provider labels overridden by the labels of a
+// resource.
+func mergeLabels(client *Client, labels map[string]string) map[string]string {
+	var defaults map[string]string
+	if client != nil {
+		defaults = client.DefaultLabels
+	}
+
+	merged := make(map[string]string, len(defaults)+len(labels))
+	for k, v := range defaults {
+		merged[k] = v
+	}
+	for k, v := range labels {
+		merged[k] = v
+	}
+	return merged
+}
+
+// expandLabelsFromTF returns the labels to send to the server for the labels
+// of a resource, including the provider labels.
+func expandLabelsFromTF(ctx context.Context, client *Client, values types.Map, diags *diag.Diagnostics) map[string]string {
+	labels := expandStringMapFromTF(ctx, values, diags)
+	if diags.HasError() {
+		return nil
+	}
+	return mergeLabels(client, labels)
+}
+
+// flattenLabelsToTFMap is flattenStringMapToTFMap for the labels of a
+// resource. Provider labels are left out unless the resource sets them
+// itself, so that they never show up as a difference to the configuration.
+// In additive mode, only the keys in existing are kept.
+func flattenLabelsToTFMap(
+	client *Client,
+	values map[string]string,
+	existing types.Map,
+	additive bool,
+	diags *diag.Diagnostics,
+) *types.Map {
+	var defaults map[string]string
+	if client != nil {
+		defaults = client.DefaultLabels
+	}
+
+	labels := make(map[string]string, len(values))
+	for k, v := range values {
+		if _, declared := existing.Elements()[k]; additive && !declared {
+			continue
+		}
+		if value, ok := defaults[k]; ok && value == v {
+			if _, declared := existing.Elements()[k]; !declared {
+				continue
+			}
+		}
+		labels[k] = v
+	}
+	return flattenStringMapToTFMap(labels, existing, diags)
+}
+
+// labelsAllValue returns the labels_all value for the labels of an object on
+// the server.
+func labelsAllValue(values map[string]string, diags *diag.Diagnostics) types.Map {
+	if len(values) == 0 {
+		return types.MapNull(types.StringType)
+	}
+
+	elements := make(map[string]attr.Value, len(values))
+	for k, v := range values {
+		elements[k] = types.StringValue(v)
+	}
+	value, valueDiags := types.MapValue(types.StringType, elements)
+	diags.Append(valueDiags...)
+	return value
+}
+
+// planLabelsAll plans labels_all from the planned labels and the provider
+// labels. It reports whether labels_all of an existing object changes, which
+// also happens without a change of the configuration when default_labels
+// change, so that the caller can mark the computed attributes refreshed by
+// the update unknown.
+func planLabelsAll(ctx context.Context, client *Client, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) bool {
+	var labels types.Map
+	var mode types.String
+	resp.Diagnostics.Append(resp.Plan.GetAttribute(ctx, path.Root("labels"), &labels)...)
+	resp.Diagnostics.Append(resp.Plan.GetAttribute(ctx, path.Root("labels_mode"), &mode)...)
+	if resp.Diagnostics.HasError() {
+		return false
+	}
+
+	known := !labels.IsUnknown() && !hasUnknownElements(labels) && !mode.IsUnknown()
+	var merged map[string]string
+	if known {
+		merged = mergeLabels(client, expandStringMapFromTF(ctx, labels, &resp.Diagnostics))
+	}
+
+	if req.State.Raw.IsNull() {
+		planned := types.MapUnknown(types.StringType)
+		if known {
+			planned = labelsAllValue(merged, &resp.Diagnostics)
+		}
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("labels_all"), planned)...)
+		return false
+	}
+
+	var stateLabels, stateLabelsAll types.Map
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("labels"), &stateLabels)...)
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("labels_all"), &stateLabelsAll)...)
+	if resp.Diagnostics.HasError() {
+		return false
+	}
+
+	// In additive mode, labels_all also holds the labels of others, which
+	// can change until the update, so changes are only known after apply.
+	planned := types.MapUnknown(types.StringType)
+	if known {
+		if isAdditiveLabels(mode) {
+			current := expandStringMapFromTF(ctx, stateLabelsAll, &resp.Diagnostics)
+			applied := appliedProviderLabels(ctx, req.Private, &resp.Diagnostics)
+			merged = additiveLabels(current, stateLabels, applied, merged)
+		}
+		planned = labelsAllValue(merged, &resp.Diagnostics)
+		if resp.Diagnostics.HasError() {
+			return false
+		}
+		if planned.Equal(stateLabelsAll) {
+			resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("labels_all"), stateLabelsAll)...)
+			return false
+		}
+		if isAdditiveLabels(mode) {
+			planned = types.MapUnknown(types.StringType)
+		}
+	}
+
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("labels_all"), planned)...)
+	return true
+}
+
+func hasUnknownElements(values types.Map) bool {
+	for _, v := range values.Elements() {
+		if v.IsUnknown() {
+			return true
+		}
+	}
+	return false
+}
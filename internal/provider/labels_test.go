@@ -0,0 +1,108 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestProviderLabels(t *testing.T) {
+	t.Setenv("TF_WORKSPACE", "")
+	t.Setenv("TFC_WORKSPACE_NAME", "platform-prod")
+
+	labels := providerLabels(map[string]string{"team": "ml", managedByLabel: "terraform-ci"}, "")
+	if labels[workspaceLabel] != "platform-prod" || labels["team"] != "ml" {
+		t.Errorf("expected the default labels and the workspace, got %v", labels)
+	}
+	if labels[managedByLabel] != "terraform-ci" {
+		t.Errorf("expected default_labels to override the managed-by label, got %v", labels)
+	}
+
+	t.Setenv("TFC_WORKSPACE_NAME", "")
+	if _, ok := providerLabels(nil, "")[workspaceLabel]; ok {
+		t.Errorf("expected no workspace label without a workspace")
+	}
+}
+
+func TestLabelsRoundTrip_KeepsProviderLabelsOutOfLabels(t *testing.T) {
+	ctx := context.Background()
+	client := &Client{DefaultLabels: map[string]string{managedByLabel: managedByValue, "team": "ml"}}
+
+	var diags diag.Diagnostics
+	configured, _ := types.MapValueFrom(ctx, types.StringType, map[string]string{"env": "prod", "team": "platform"})
+	sent := expandLabelsFromTF(ctx, client, configured, &diags)
+	if sent[managedByLabel] != managedByValue || sent["team"] != "platform" || sent["env"] != "prod" {
+		t.Errorf("expected the provider labels to be merged under the resource labels, got %v", sent)
+	}
+
+	// A default label set outside of Terraform to the default value is not
+	// reported, one with another value is.
+	server := map[string]string{managedByLabel: managedByValue, "team": "ml", "env": "prod"}
+	flattened := flattenLabelsToTFMap(client, server, configured, false, &diags)
+	if diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+	elements := flattened.Elements()
+	if _, ok := elements[managedByLabel]; ok || len(elements) != 2 {
+		t.Errorf("expected only the configured labels, got %s", flattened)
+	}
+	if elements["team"].(types.String).ValueString() != "ml" {
+		t.Errorf("expected the drift of a configured label to be reported, got %s", flattened)
+	}
+
+	all := labelsAllValue(server, &diags)
+	if len(all.Elements()) != 3 {
+		t.Errorf("expected labels_all to hold all labels, got %s", all)
+	}
+}
+
+func TestAdditiveLabels_KeepsForeignLabels(t *testing.T) {
+	ctx := context.Background()
+	client := &Client{DefaultLabels: map[string]string{managedByLabel: managedByValue}}
+
+	var diags diag.Diagnostics
+	previous, _ := types.MapValueFrom(ctx, types.StringType, map[string]string{"env": "prod", "owner": "ml"})
+	configured, _ := types.MapValueFrom(ctx, types.StringType, map[string]string{"env": "staging"})
+	server := map[string]string{managedByLabel: managedByValue, "env": "prod", "owner": "ml", "cost-center": "1234"}
+
+	// owner is no longer configured, so it is removed, while cost-center was
+	// added by someone else and is kept.
+	sent := additiveLabels(server, previous, nil, expandLabelsFromTF(ctx, client, configured, &diags))
+	if _, ok := sent["owner"]; ok || sent["env"] != "staging" || sent["cost-center"] != "1234" || sent[managedByLabel] != managedByValue {
+		t.Errorf("unexpected additive labels %v", sent)
+	}
+
+	flattened := flattenLabelsToTFMap(client, sent, configured, true, &diags)
+	if diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+	if len(flattened.Elements()) != 1 {
+		t.Errorf("expected foreign labels to be left out of labels, got %s", flattened)
+	}
+}
+
+func TestAdditiveLabels_RemovesDroppedDefaultLabels(t *testing.T) {
+	ctx := context.Background()
+	applied := map[string]string{managedByLabel: managedByValue, "team": "ml", "cost-center": "1234"}
+	client := &Client{DefaultLabels: map[string]string{managedByLabel: managedByValue, "team": "ml"}}
+
+	var diags diag.Diagnostics
+	previous, _ := types.MapValueFrom(ctx, types.StringType, map[string]string{"env": "prod"})
+	server := map[string]string{managedByLabel: managedByValue, "team": "ml", "cost-center": "1234", "env": "prod", "owner": "ml"}
+
+	// cost-center was dropped from default_labels, so it is removed, while
+	// owner was added by someone else and is kept.
+	sent := additiveLabels(server, previous, applied, expandLabelsFromTF(ctx, client, previous, &diags))
+	if _, ok := sent["cost-center"]; ok || sent["team"] != "ml" || sent["owner"] != "ml" || sent["env"] != "prod" {
+		t.Errorf("unexpected additive labels %v", sent)
+	}
+
+	// A default label that someone else changed since is theirs.
+	server["cost-center"] = "5678"
+	sent = additiveLabels(server, previous, applied, expandLabelsFromTF(ctx, client, previous, &diags))
+	if sent["cost-center"] != "5678" {
+		t.Errorf("expected a changed default label to be kept, got %v", sent)
+	}
+}
@@ -0,0 +1,106 @@
+// list.go
+package provider
+
+import (
+	"context"
+	"fmt"
+	"iter"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/list"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// listResourceClient extracts the ZenML client from the provider data passed to
+// list resources.
+func listResourceClient(req resource.ConfigureRequest, resp *resource.ConfigureResponse) *Client {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return nil
+	}
+
+	client, ok := req.ProviderData.(*Client)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected List Resource Configure Type",
+			fmt.Sprintf("Expected *Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return nil
+	}
+
+	return client
+}
+
+// listResults pages through the objects returned by listPage and streams one
+// list result per object accepted by keep, stopping once the limit requested
+// by Terraform is reached. Pages are only fetched as Terraform consumes the
+// results, so large inventories are never held in memory at once.
+func listResults[T any](
+	ctx context.Context,
+	req list.ListRequest,
+	kind string,
+	params *ListParams,
+	listPage func(context.Context, *ListParams) (*Page[T], error),
+	keep func(T) bool,
+	populate func(context.Context, T, *list.ListResult),
+) iter.Seq[list.ListResult] {
+	return func(push func(list.ListResult) bool) {
+		var count int64
+		for {
+			page, err := listPage(ctx, params)
+			if err != nil {
+				var diags diag.Diagnostics
+				diags.AddError(
+					"Client Error",
+					fmt.Sprintf("Unable to list %ss, got error: %s", kind, err),
+				)
+				push(list.ListResult{Diagnostics: diags})
+				return
+			}
+
+			for _, item := range page.Items {
+				if keep != nil && !keep(item) {
+					continue
+				}
+
+				result := req.NewListResult(ctx)
+				populate(ctx, item, &result)
+				if !push(result) {
+					return
+				}
+
+				count++
+				if req.Limit > 0 && count >= req.Limit {
+					return
+				}
+			}
+
+			if page.Index >= page.TotalPages {
+				return
+			}
+			params.Page = page.Index + 1
+		}
+	}
+}
+
+// newListResultModel initializes the resource of a list result with null
+// values for all attributes and decodes it into target, so that models can be
+// populated the same way as in Read.
+func newListResultModel(ctx context.Context, res *tfsdk.Resource, target any) diag.Diagnostics {
+	res.Raw = nullAttributesValue(res.Schema.Type().TerraformType(ctx))
+
+	return res.Get(ctx, target)
+}
+
+// nullAttributesValue returns a known object of the given schema type with
+// null values for all attributes.
+func nullAttributesValue(schemaType tftypes.Type) tftypes.Value {
+	objectType := schemaType.(tftypes.Object)
+	values := make(map[string]tftypes.Value, len(objectType.AttributeTypes))
+	for name, attrType := range objectType.AttributeTypes {
+		values[name] = tftypes.NewValue(attrType, nil)
+	}
+	return tftypes.NewValue(objectType, values)
+}
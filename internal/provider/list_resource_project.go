@@ -0,0 +1,97 @@
+// list_resource_project.go
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/list"
+	"github.com/hashicorp/terraform-plugin-framework/list/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var _ list.ListResource = &ProjectListResource{}
+var _ list.ListResourceWithConfigure = &ProjectListResource{}
+
+func NewProjectListResource() list.ListResource {
+	return &ProjectListResource{}
+}
+
+type ProjectListResource struct {
+	client *Client
+}
+
+type ProjectListResourceModel struct {
+	Name types.String `tfsdk:"name"`
+}
+
+func (r *ProjectListResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_project"
+}
+
+func (r *ProjectListResource) ListResourceConfigSchema(ctx context.Context, req list.ListResourceSchemaRequest, resp *list.ListResourceSchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Lists the ZenML projects on the server.",
+		Attributes: map[string]schema.Attribute{
+			"name": schema.StringAttribute{
+				MarkdownDescription: "Only list projects matching this name. " +
+					"ZenML filter operators such as `contains:ml` are supported.",
+				Optional: true,
+			},
+		},
+	}
+}
+
+func (r *ProjectListResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	r.client = listResourceClient(req, resp)
+}
+
+func (r *ProjectListResource) List(ctx context.Context, req list.ListRequest, stream *list.ListResultsStream) {
+	var data ProjectListResourceModel
+	if !req.Config.Raw.IsNull() {
+		diags := req.Config.Get(ctx, &data)
+		if diags.HasError() {
+			stream.Results = list.ListResultsStreamDiagnostics(diags)
+			return
+		}
+	}
+
+	params := &ListParams{Filter: map[string]string{}}
+	if !data.Name.IsNull() {
+		params.Filter["name"] = data.Name.ValueString()
+	}
+
+	projects := &ProjectResource{client: r.client}
+	stream.Results = listResults(ctx, req, "project", params, r.client.ListProjects, nil,
+		func(ctx context.Context, item ProjectResponse, result *list.ListResult) {
+			result.DisplayName = item.Name
+			setResourceIdentity(ctx, r.client, item.ID, result.Identity, &result.Diagnostics)
+			if !req.IncludeResource || result.Diagnostics.HasError() {
+				return
+			}
+
+			project, err := r.client.GetProject(ctx, item.ID)
+			if err != nil {
+				result.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read project %s, got error: %s", item.ID, err))
+				return
+			}
+			if project == nil {
+				return
+			}
+
+			var model ProjectResourceModel
+			result.Diagnostics.Append(newListResultModel(ctx, result.Resource, &model)...)
+			if result.Diagnostics.HasError() {
+				return
+			}
+			projects.populateProjectModel(ctx, project, &model, &result.Diagnostics)
+			if result.Diagnostics.HasError() {
+				return
+			}
+			model.ForceDestroy = types.BoolValue(false)
+			model.DeletionProtection = types.BoolValue(false)
+			result.Diagnostics.Append(result.Resource.Set(ctx, &model)...)
+		},
+	)
+}
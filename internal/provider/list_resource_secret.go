@@ -0,0 +1,97 @@
+// list_resource_secret.go
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/list"
+	"github.com/hashicorp/terraform-plugin-framework/list/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var _ list.ListResource = &SecretListResource{}
+var _ list.ListResourceWithConfigure = &SecretListResource{}
+
+func NewSecretListResource() list.ListResource {
+	return &SecretListResource{}
+}
+
+type SecretListResource struct {
+	client *Client
+}
+
+type SecretListResourceModel struct {
+	Name types.String `tfsdk:"name"`
+}
+
+func (r *SecretListResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_secret"
+}
+
+func (r *SecretListResource) ListResourceConfigSchema(ctx context.Context, req list.ListResourceSchemaRequest, resp *list.ListResourceSchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Lists the ZenML secrets visible to the authenticated user. " +
+			"Secret values are only read when the resources are included in the results.",
+		Attributes: map[string]schema.Attribute{
+			"name": schema.StringAttribute{
+				MarkdownDescription: "Only list secrets matching this name. " +
+					"ZenML filter operators such as `startswith:aws-` are supported.",
+				Optional: true,
+			},
+		},
+	}
+}
+
+func (r *SecretListResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	r.client = listResourceClient(req, resp)
+}
+
+func (r *SecretListResource) List(ctx context.Context, req list.ListRequest, stream *list.ListResultsStream) {
+	var data SecretListResourceModel
+	if !req.Config.Raw.IsNull() {
+		diags := req.Config.Get(ctx, &data)
+		if diags.HasError() {
+			stream.Results = list.ListResultsStreamDiagnostics(diags)
+			return
+		}
+	}
+
+	params := &ListParams{Filter: map[string]string{}}
+	if !data.Name.IsNull() {
+		params.Filter["name"] = data.Name.ValueString()
+	}
+
+	secrets := &SecretResource{client: r.client}
+	stream.Results = listResults(ctx, req, "secret", params, r.client.ListSecrets, nil,
+		func(ctx context.Context, item SecretResponse, result *list.ListResult) {
+			result.DisplayName = item.Name
+			setResourceIdentity(ctx, r.client, item.ID, result.Identity, &result.Diagnostics)
+			if !req.IncludeResource || result.Diagnostics.HasError() {
+				return
+			}
+
+			secret, err := r.client.GetSecret(ctx, item.ID)
+			if err != nil {
+				result.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read secret %s, got error: %s", item.ID, err))
+				return
+			}
+			if secret == nil {
+				return
+			}
+
+			var model SecretResourceModel
+			result.Diagnostics.Append(newListResultModel(ctx, result.Resource, &model)...)
+			if result.Diagnostics.HasError() {
+				return
+			}
+			secrets.populateSecretModel(ctx, secret, &model, &result.Diagnostics)
+			if result.Diagnostics.HasError() {
+				return
+			}
+			model.DeletionProtection = types.BoolValue(false)
+			result.Diagnostics.Append(result.Resource.Set(ctx, &model)...)
+		},
+	)
+}
@@ -0,0 +1,112 @@
+// list_resource_service_connector.go
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/list"
+	"github.com/hashicorp/terraform-plugin-framework/list/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var _ list.ListResource = &ServiceConnectorListResource{}
+var _ list.ListResourceWithConfigure = &ServiceConnectorListResource{}
+
+func NewServiceConnectorListResource() list.ListResource {
+	return &ServiceConnectorListResource{}
+}
+
+type ServiceConnectorListResource struct {
+	client *Client
+}
+
+type ServiceConnectorListResourceModel struct {
+	Name         types.String `tfsdk:"name"`
+	Type         types.String `tfsdk:"type"`
+	ResourceType types.String `tfsdk:"resource_type"`
+}
+
+func (r *ServiceConnectorListResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_service_connector"
+}
+
+func (r *ServiceConnectorListResource) ListResourceConfigSchema(ctx context.Context, req list.ListResourceSchemaRequest, resp *list.ListResourceSchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Lists the ZenML service connectors on the server.",
+		Attributes: map[string]schema.Attribute{
+			"name": schema.StringAttribute{
+				MarkdownDescription: "Only list service connectors matching this name. " +
+					"ZenML filter operators such as `contains:gcp` are supported.",
+				Optional: true,
+			},
+			"type": schema.StringAttribute{
+				MarkdownDescription: "Only list service connectors of this type, e.g. `aws`",
+				Optional:            true,
+			},
+			"resource_type": schema.StringAttribute{
+				MarkdownDescription: "Only list service connectors that provide this resource type, e.g. `s3-bucket`",
+				Optional:            true,
+			},
+		},
+	}
+}
+
+func (r *ServiceConnectorListResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	r.client = listResourceClient(req, resp)
+}
+
+func (r *ServiceConnectorListResource) List(ctx context.Context, req list.ListRequest, stream *list.ListResultsStream) {
+	var data ServiceConnectorListResourceModel
+	if !req.Config.Raw.IsNull() {
+		diags := req.Config.Get(ctx, &data)
+		if diags.HasError() {
+			stream.Results = list.ListResultsStreamDiagnostics(diags)
+			return
+		}
+	}
+
+	params := &ListParams{Filter: map[string]string{}}
+	if !data.Name.IsNull() {
+		params.Filter["name"] = data.Name.ValueString()
+	}
+	if !data.Type.IsNull() {
+		params.Filter["connector_type"] = data.Type.ValueString()
+	}
+	if !data.ResourceType.IsNull() {
+		params.Filter["resource_type"] = data.ResourceType.ValueString()
+	}
+
+	connectors := &ServiceConnectorResource{client: r.client}
+	stream.Results = listResults(ctx, req, "service connector", params, r.client.ListServiceConnectors, nil,
+		func(ctx context.Context, item ServiceConnectorResponse, result *list.ListResult) {
+			result.DisplayName = item.Name
+			setResourceIdentity(ctx, r.client, item.ID, result.Identity, &result.Diagnostics)
+			if !req.IncludeResource || result.Diagnostics.HasError() {
+				return
+			}
+
+			connector, err := r.client.GetServiceConnector(ctx, item.ID)
+			if err != nil {
+				result.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read service connector %s, got error: %s", item.ID, err))
+				return
+			}
+			if connector == nil {
+				return
+			}
+
+			var model ServiceConnectorResourceModel
+			result.Diagnostics.Append(newListResultModel(ctx, result.Resource, &model)...)
+			if result.Diagnostics.HasError() {
+				return
+			}
+			connectors.populateServiceConnectorModel(ctx, connector, &model, &result.Diagnostics, configurationAdopt)
+			if result.Diagnostics.HasError() {
+				return
+			}
+			model.DeletionProtection = types.BoolValue(false)
+			result.Diagnostics.Append(result.Resource.Set(ctx, &model)...)
+		},
+	)
+}
@@ -0,0 +1,96 @@
+// list_resource_stack.go
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/list"
+	"github.com/hashicorp/terraform-plugin-framework/list/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var _ list.ListResource = &StackListResource{}
+var _ list.ListResourceWithConfigure = &StackListResource{}
+
+func NewStackListResource() list.ListResource {
+	return &StackListResource{}
+}
+
+type StackListResource struct {
+	client *Client
+}
+
+type StackListResourceModel struct {
+	Name types.String `tfsdk:"name"`
+}
+
+func (r *StackListResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_stack"
+}
+
+func (r *StackListResource) ListResourceConfigSchema(ctx context.Context, req list.ListResourceSchemaRequest, resp *list.ListResourceSchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Lists the ZenML stacks on the server.",
+		Attributes: map[string]schema.Attribute{
+			"name": schema.StringAttribute{
+				MarkdownDescription: "Only list stacks matching this name. " +
+					"ZenML filter operators such as `contains:prod` are supported.",
+				Optional: true,
+			},
+		},
+	}
+}
+
+func (r *StackListResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	r.client = listResourceClient(req, resp)
+}
+
+func (r *StackListResource) List(ctx context.Context, req list.ListRequest, stream *list.ListResultsStream) {
+	var data StackListResourceModel
+	if !req.Config.Raw.IsNull() {
+		diags := req.Config.Get(ctx, &data)
+		if diags.HasError() {
+			stream.Results = list.ListResultsStreamDiagnostics(diags)
+			return
+		}
+	}
+
+	params := &ListParams{Filter: map[string]string{}}
+	if !data.Name.IsNull() {
+		params.Filter["name"] = data.Name.ValueString()
+	}
+
+	stacks := &StackResource{client: r.client}
+	stream.Results = listResults(ctx, req, "stack", params, r.client.ListStacks, nil,
+		func(ctx context.Context, item StackResponse, result *list.ListResult) {
+			result.DisplayName = item.Name
+			setResourceIdentity(ctx, r.client, item.ID, result.Identity, &result.Diagnostics)
+			if !req.IncludeResource || result.Diagnostics.HasError() {
+				return
+			}
+
+			stack, err := r.client.GetStack(ctx, item.ID)
+			if err != nil {
+				result.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read stack %s, got error: %s", item.ID, err))
+				return
+			}
+			if stack == nil {
+				return
+			}
+
+			var model StackResourceModel
+			result.Diagnostics.Append(newListResultModel(ctx, result.Resource, &model)...)
+			if result.Diagnostics.HasError() {
+				return
+			}
+			stacks.populateStackModel(ctx, stack, &model, &result.Diagnostics)
+			if result.Diagnostics.HasError() {
+				return
+			}
+			model.DeletionProtection = types.BoolValue(false)
+			result.Diagnostics.Append(result.Resource.Set(ctx, &model)...)
+		},
+	)
+}
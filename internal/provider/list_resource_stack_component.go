@@ -0,0 +1,153 @@
+// list_resource_stack_component.go
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/list"
+	"github.com/hashicorp/terraform-plugin-framework/list/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var _ list.ListResource = &StackComponentListResource{}
+var _ list.ListResourceWithConfigure = &StackComponentListResource{}
+
+func NewStackComponentListResource() list.ListResource {
+	return &StackComponentListResource{}
+}
+
+type StackComponentListResource struct {
+	client *Client
+}
+
+type StackComponentListResourceModel struct {
+	Name   types.String `tfsdk:"name"`
+	Type   types.String `tfsdk:"type"`
+	Flavor types.String `tfsdk:"flavor"`
+	Labels types.Map    `tfsdk:"labels"`
+}
+
+func (r *StackComponentListResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_stack_component"
+}
+
+func (r *StackComponentListResource) ListResourceConfigSchema(ctx context.Context, req list.ListResourceSchemaRequest, resp *list.ListResourceSchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Lists the ZenML stack components on the server.",
+		Attributes: map[string]schema.Attribute{
+			"name": schema.StringAttribute{
+				MarkdownDescription: "Only list components matching this name. " +
+					"ZenML filter operators such as `contains:s3` are supported.",
+				Optional: true,
+			},
+			"type": schema.StringAttribute{
+				MarkdownDescription: "Only list components of this type, e.g. `artifact_store`",
+				Optional:            true,
+			},
+			"flavor": schema.StringAttribute{
+				MarkdownDescription: "Only list components of this flavor, e.g. `s3`",
+				Optional:            true,
+			},
+			"labels": schema.MapAttribute{
+				MarkdownDescription: "Only list components that have all of these labels",
+				ElementType:         types.StringType,
+				Optional:            true,
+			},
+		},
+	}
+}
+
+func (r *StackComponentListResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	r.client = listResourceClient(req, resp)
+}
+
+func (r *StackComponentListResource) List(ctx context.Context, req list.ListRequest, stream *list.ListResultsStream) {
+	var data StackComponentListResourceModel
+	if !req.Config.Raw.IsNull() {
+		diags := req.Config.Get(ctx, &data)
+		if diags.HasError() {
+			stream.Results = list.ListResultsStreamDiagnostics(diags)
+			return
+		}
+	}
+
+	params := &ListParams{Filter: map[string]string{}}
+	if !data.Name.IsNull() {
+		params.Filter["name"] = data.Name.ValueString()
+	}
+	if !data.Type.IsNull() {
+		params.Filter["type"] = data.Type.ValueString()
+	}
+	if !data.Flavor.IsNull() {
+		params.Filter["flavor"] = data.Flavor.ValueString()
+	}
+
+	// Labels cannot be filtered on by the server, so they are matched against
+	// the hydrated components instead.
+	var labels map[string]string
+	if !data.Labels.IsNull() && len(data.Labels.Elements()) > 0 {
+		diags := data.Labels.ElementsAs(ctx, &labels, false)
+		if diags.HasError() {
+			stream.Results = list.ListResultsStreamDiagnostics(diags)
+			return
+		}
+		params.Filter["hydrate"] = "true"
+	}
+
+	components := &StackComponentResource{client: r.client}
+	stream.Results = listResults(ctx, req, "stack component", params, r.client.ListStackComponents,
+		func(item ComponentResponse) bool {
+			return componentHasLabels(item, labels)
+		},
+		func(ctx context.Context, item ComponentResponse, result *list.ListResult) {
+			result.DisplayName = item.Name
+			if item.Body != nil {
+				result.DisplayName = item.Body.Type + "/" + item.Name
+			}
+			setResourceIdentity(ctx, r.client, item.ID, result.Identity, &result.Diagnostics)
+			if !req.IncludeResource || result.Diagnostics.HasError() {
+				return
+			}
+
+			component, err := r.client.GetComponent(ctx, item.ID)
+			if err != nil {
+				result.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read stack component %s, got error: %s", item.ID, err))
+				return
+			}
+			if component == nil {
+				return
+			}
+
+			var model StackComponentResourceModel
+			result.Diagnostics.Append(newListResultModel(ctx, result.Resource, &model)...)
+			if result.Diagnostics.HasError() {
+				return
+			}
+			components.populateStackComponentModel(ctx, component, &model, &result.Diagnostics, configurationAdopt)
+			if result.Diagnostics.HasError() {
+				return
+			}
+			model.DeletionProtection = types.BoolValue(false)
+			result.Diagnostics.Append(result.Resource.Set(ctx, &model)...)
+		},
+	)
+}
+
+// componentHasLabels reports whether the component has all of the given
+// labels.
+func componentHasLabels(component ComponentResponse, labels map[string]string) bool {
+	if len(labels) == 0 {
+		return true
+	}
+	if component.Metadata == nil {
+		return false
+	}
+	for k, v := range labels {
+		if value, ok := component.Metadata.Labels[k]; !ok || value != v {
+			return false
+		}
+	}
+	return true
+}
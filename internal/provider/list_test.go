@@ -0,0 +1,178 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/list"
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+)
+
+func TestListResourceSchemas(t *testing.T) {
+	ctx := context.Background()
+	server, err := providerserver.NewProtocol6WithError(New("test")())()
+	if err != nil {
+		t.Fatalf("unexpected error creating provider server: %s", err)
+	}
+
+	resp, err := server.GetProviderSchema(ctx, &tfprotov6.GetProviderSchemaRequest{})
+	if err != nil {
+		t.Fatalf("unexpected error getting provider schema: %s", err)
+	}
+	for _, d := range resp.Diagnostics {
+		if d.Severity == tfprotov6.DiagnosticSeverityError {
+			t.Fatalf("unexpected provider schema error: %s: %s", d.Summary, d.Detail)
+		}
+	}
+
+	for _, name := range []string{
+		"zenml_stack",
+		"zenml_stack_component",
+		"zenml_service_connector",
+		"zenml_secret",
+		"zenml_project",
+	} {
+		if _, ok := resp.ListResourceSchemas[name]; !ok {
+			t.Errorf("expected a list resource schema for %s", name)
+		}
+	}
+}
+
+func testStackListRequest(t *testing.T, limit int64) list.ListRequest {
+	t.Helper()
+	ctx := context.Background()
+
+	schemaResp := &resource.SchemaResponse{}
+	(&StackResource{}).Schema(ctx, resource.SchemaRequest{}, schemaResp)
+	identityResp := &resource.IdentitySchemaResponse{}
+	(&StackResource{}).IdentitySchema(ctx, resource.IdentitySchemaRequest{}, identityResp)
+
+	return list.ListRequest{
+		Limit:                  limit,
+		ResourceSchema:         schemaResp.Schema,
+		ResourceIdentitySchema: identityResp.IdentitySchema,
+	}
+}
+
+func TestListResults_PagesAndLimit(t *testing.T) {
+	ctx := context.Background()
+	pages := [][]StackResponse{
+		{{ID: "1", Name: "a"}, {ID: "2", Name: "b"}},
+		{{ID: "3", Name: "c"}, {ID: "4", Name: "d"}},
+	}
+	var requested []int
+	listPage := func(ctx context.Context, params *ListParams) (*Page[StackResponse], error) {
+		if params.Page == 0 {
+			params.Page = 1
+		}
+		requested = append(requested, params.Page)
+		return &Page[StackResponse]{
+			Index:      params.Page,
+			TotalPages: len(pages),
+			Items:      pages[params.Page-1],
+		}, nil
+	}
+	populate := func(ctx context.Context, item StackResponse, result *list.ListResult) {
+		result.DisplayName = item.Name
+	}
+
+	collect := func(limit int64, keep func(StackResponse) bool) []string {
+		requested = nil
+		var names []string
+		results := listResults(ctx, testStackListRequest(t, limit), "stack", &ListParams{}, listPage, keep, populate)
+		for result := range results {
+			names = append(names, result.DisplayName)
+		}
+		return names
+	}
+
+	if got := fmt.Sprint(collect(0, nil)); got != "[a b c d]" {
+		t.Errorf("expected all stacks, got %s", got)
+	}
+	if got := fmt.Sprint(collect(2, nil)); got != "[a b]" || len(requested) != 1 {
+		t.Errorf("expected the first page only, got %s after requesting pages %v", got, requested)
+	}
+	keep := func(s StackResponse) bool { return s.ID != "2" }
+	if got := fmt.Sprint(collect(2, keep)); got != "[a c]" {
+		t.Errorf("expected filtered stacks, got %s", got)
+	}
+}
+
+func TestListResults_ClientError(t *testing.T) {
+	ctx := context.Background()
+	listPage := func(ctx context.Context, params *ListParams) (*Page[StackResponse], error) {
+		return nil, fmt.Errorf("boom")
+	}
+
+	var results []list.ListResult
+	for result := range listResults(ctx, testStackListRequest(t, 0), "stack", &ListParams{}, listPage, nil, nil) {
+		results = append(results, result)
+	}
+	if len(results) != 1 || !results[0].Diagnostics.HasError() {
+		t.Fatalf("expected a single error result, got %v", results)
+	}
+}
+
+func TestComponentHasLabels(t *testing.T) {
+	component := ComponentResponse{
+		Metadata: &ComponentResponseMetadata{
+			Labels: map[string]string{"team": "ml", "env": "prod"},
+		},
+	}
+
+	cases := []struct {
+		labels map[string]string
+		want   bool
+	}{
+		{nil, true},
+		{map[string]string{"team": "ml"}, true},
+		{map[string]string{"team": "ml", "env": "prod"}, true},
+		{map[string]string{"team": "data"}, false},
+		{map[string]string{"owner": "ml"}, false},
+	}
+
+	for _, tc := range cases {
+		if got := componentHasLabels(component, tc.labels); got != tc.want {
+			t.Errorf("componentHasLabels(%v) = %v, want %v", tc.labels, got, tc.want)
+		}
+	}
+
+	if componentHasLabels(ComponentResponse{}, map[string]string{"team": "ml"}) {
+		t.Error("expected a component without metadata not to match labels")
+	}
+}
+
+func TestNewListResultModel(t *testing.T) {
+	ctx := context.Background()
+
+	schemaResp := &resource.SchemaResponse{}
+	(&ServiceConnectorResource{}).Schema(ctx, resource.SchemaRequest{}, schemaResp)
+	result := list.ListRequest{
+		ResourceSchema:         schemaResp.Schema,
+		ResourceIdentitySchema: resourceIdentitySchema("service connector"),
+	}.NewListResult(ctx)
+
+	var model ServiceConnectorResourceModel
+	if diags := newListResultModel(ctx, result.Resource, &model); diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+
+	connector := &ServiceConnectorResponse{
+		ID:   "connector-1",
+		Name: "gcp",
+		Body: &ServiceConnectorResponseBody{AuthMethod: "service-account"},
+		Metadata: &ServiceConnectorResponseMetadata{
+			Labels: map[string]string{"team": "ml"},
+		},
+	}
+	var diags diag.Diagnostics
+	(&ServiceConnectorResource{}).populateServiceConnectorModel(ctx, connector, &model, &diags, configurationAdopt)
+	diags.Append(result.Resource.Set(ctx, &model)...)
+	if diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+}
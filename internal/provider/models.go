@@ -43,9 +43,11 @@ type ServerInfo struct {
 
 // StackRequest represents a request to create a new stack
 type StackRequest struct {
-	Name       string              `json:"name"`
-	Components map[string][]string `json:"components"` // Change to UUID strings
-	Labels     map[string]string   `json:"labels"`
+	Name        string              `json:"name"`
+	Components  map[string][]string `json:"components"` // Change to UUID strings
+	Labels      map[string]string   `json:"labels"`
+	Environment map[string]string   `json:"environment,omitempty"`
+	Secrets     []string            `json:"secrets,omitempty"`
 }
 
 // StackResponse represents a stack response from the API
@@ -63,15 +65,20 @@ type StackResponseBody struct {
 }
 
 type StackResponseMetadata struct {
-	Components map[string][]ComponentResponse `json:"components"`
-	Labels     map[string]string              `json:"labels,omitempty"`
+	Components  map[string][]ComponentResponse `json:"components"`
+	Labels      map[string]string              `json:"labels,omitempty"`
+	Environment map[string]string              `json:"environment,omitempty"`
+	Secrets     []string                       `json:"secrets,omitempty"`
 }
 
 // StackUpdate represents an update to an existing stack
 type StackUpdate struct {
-	Name       string              `json:"name"`
-	Components map[string][]string `json:"components,omitempty"` // Only UUIDs for updates
-	Labels     map[string]string   `json:"labels"`
+	Name          string              `json:"name"`
+	Components    map[string][]string `json:"components,omitempty"` // Only UUIDs for updates
+	Labels        map[string]string   `json:"labels"`
+	Environment   map[string]string   `json:"environment"`
+	AddSecrets    []string            `json:"add_secrets,omitempty"`
+	RemoveSecrets []string            `json:"remove_secrets,omitempty"`
 }
 
 // ComponentRequest represents a request to create a new component
@@ -197,10 +204,12 @@ type ServiceConnectorTypedResources struct {
 	Error        *string  `json:"error,omitempty"`
 }
 
-// ServiceConnectorUpdate represents an update to an existing service connector
+// ServiceConnectorUpdate represents an update to an existing service
+// connector. Secrets are left unchanged when nil and cleared when empty.
 type ServiceConnectorUpdate struct {
 	Name          string                 `json:"name"`
 	Configuration map[string]interface{} `json:"configuration"`
+	Secrets       *map[string]string     `json:"secrets,omitempty"`
 	Labels        map[string]string      `json:"labels"`
 	ResourceTypes []string               `json:"resource_types"`
 	ResourceID    *string                `json:"resource_id"`
@@ -225,6 +234,7 @@ type UserResponseBody struct {
 	EmailOptedIn     bool    `json:"email_opted_in"`
 	IsServiceAccount bool    `json:"is_service_account"`
 	IsAdmin          bool    `json:"is_admin"`
+	DefaultProjectID *string `json:"default_project_id,omitempty"`
 }
 
 type UserResponseMetadata struct {
@@ -257,9 +267,10 @@ type ProjectResponse struct {
 }
 
 type ProjectResponseBody struct {
-	Created     string `json:"created"`
-	Updated     string `json:"updated"`
-	DisplayName string `json:"display_name"`
+	Created        string  `json:"created"`
+	Updated        string  `json:"updated"`
+	DisplayName    string  `json:"display_name"`
+	DefaultStackID *string `json:"default_stack_id,omitempty"`
 }
 
 type ProjectResponseMetadata struct {
@@ -268,9 +279,22 @@ type ProjectResponseMetadata struct {
 
 // ProjectUpdate represents an update to an existing project
 type ProjectUpdate struct {
-	Name        *string `json:"name,omitempty"`
-	DisplayName *string `json:"display_name,omitempty"`
-	Description *string `json:"description,omitempty"`
+	Name           *string `json:"name,omitempty"`
+	DisplayName    *string `json:"display_name,omitempty"`
+	Description    *string `json:"description,omitempty"`
+	DefaultStackID *string `json:"default_stack_id,omitempty"`
+}
+
+// PipelineResponse represents a pipeline returned by the ZenML API.
+type PipelineResponse struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// PipelineRunResponse represents a pipeline run returned by the ZenML API.
+type PipelineRunResponse struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
 }
 
 // SecretRequest represents a request to create a ZenML secret.
@@ -287,6 +311,12 @@ type SecretUpdate struct {
 	Values  map[string]string `json:"values"`
 }
 
+// SecretValuesPatch changes single values of a ZenML secret and keeps the
+// others. A nil value removes the key.
+type SecretValuesPatch struct {
+	Values map[string]*string `json:"values"`
+}
+
 // SecretResponse represents a secret returned by the ZenML API.
 type SecretResponse struct {
 	ID   string              `json:"id"`
@@ -301,3 +331,23 @@ type SecretResponseBody struct {
 	Private bool               `json:"private"`
 	Values  map[string]*string `json:"values"`
 }
+
+// FlavorResponse represents a stack component flavor returned by the ZenML
+// API.
+type FlavorResponse struct {
+	ID       string                  `json:"id,omitempty"`
+	Name     string                  `json:"name"`
+	Body     *FlavorResponseBody     `json:"body,omitempty"`
+	Metadata *FlavorResponseMetadata `json:"metadata,omitempty"`
+}
+
+type FlavorResponseBody struct {
+	Type        string  `json:"type"`
+	Integration *string `json:"integration,omitempty"`
+}
+
+type FlavorResponseMetadata struct {
+	ConfigSchema          map[string]interface{} `json:"config_schema"`
+	ConnectorType         *string                `json:"connector_type,omitempty"`
+	ConnectorResourceType *string                `json:"connector_resource_type,omitempty"`
+}
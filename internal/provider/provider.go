@@ -8,6 +8,7 @@ import (
 	"github.com/hashicorp/go-version"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/list"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
@@ -18,6 +19,7 @@ import (
 
 var _ provider.Provider = &ZenMLProvider{}
 var _ provider.ProviderWithFunctions = &ZenMLProvider{}
+var _ provider.ProviderWithListResources = &ZenMLProvider{}
 
 type ZenMLProvider struct {
 	// version is set to the provider version on release, "dev" when the
@@ -31,6 +33,9 @@ type ZenMLProviderModel struct {
 	APIKey           types.String `tfsdk:"api_key"`
 	APIToken         types.String `tfsdk:"api_token"`
 	SkipVersionCheck types.Bool   `tfsdk:"skip_version_check"`
+	AdoptExisting    types.Bool   `tfsdk:"adopt_existing"`
+	DefaultLabels    types.Map    `tfsdk:"default_labels"`
+	Workspace        types.String `tfsdk:"workspace"`
 }
 
 func (p *ZenMLProvider) Metadata(ctx context.Context, req provider.MetadataRequest, resp *provider.MetadataResponse) {
@@ -59,6 +64,24 @@ func (p *ZenMLProvider) Schema(ctx context.Context, req provider.SchemaRequest,
 				MarkdownDescription: "Skip the ZenML server version compatibility check. Use with caution as it may lead to unexpected behavior.",
 				Optional:            true,
 			},
+			"adopt_existing": schema.BoolAttribute{
+				MarkdownDescription: "Whether stacks and stack components adopt an existing object with the same name " +
+					"instead of failing to create it, for example after a failed run. Resources can override it " +
+					"with their own `adopt_existing` attribute. Defaults to `false`.",
+				Optional: true,
+			},
+			"default_labels": schema.MapAttribute{
+				MarkdownDescription: "Labels added to every stack, stack component and service connector. " +
+					"Labels set on a resource take precedence. The provider also adds a `managed-by = \"terraform\"` " +
+					"label and, if the workspace is known, a `terraform-workspace` label.",
+				ElementType: types.StringType,
+				Optional:    true,
+			},
+			"workspace": schema.StringAttribute{
+				MarkdownDescription: "Identifier of the Terraform workspace for the `terraform-workspace` label. " +
+					"Defaults to the `TF_WORKSPACE` or `TFC_WORKSPACE_NAME` environment variable.",
+				Optional: true,
+			},
 		},
 	}
 }
@@ -191,6 +214,11 @@ func (p *ZenMLProvider) Configure(ctx context.Context, req provider.ConfigureReq
 		)
 		return
 	}
+	client.AdoptExisting = data.AdoptExisting.ValueBool()
+	client.DefaultLabels = providerLabels(
+		expandStringMapFromTF(ctx, data.DefaultLabels, &resp.Diagnostics),
+		data.Workspace.ValueString(),
+	)
 
 	// Test the client connection
 	serverInfo, err := client.GetServerInfo(ctx)
@@ -254,22 +282,26 @@ func (p *ZenMLProvider) Configure(ctx context.Context, req provider.ConfigureReq
 		}
 	}
 
-	// Make the ZenML client available during DataSource and Resource
-	// type Configure methods.
+	// Make the ZenML client available during DataSource, Resource and
+	// ListResource type Configure methods.
 	resp.DataSourceData = client
 	resp.ResourceData = client
+	resp.ListResourceData = client
 
 	tflog.Info(ctx, "Configured ZenML client", map[string]any{"success": true})
 }
 
 func (p *ZenMLProvider) Resources(ctx context.Context) []func() resource.Resource {
-	return []func() resource.Resource{
+	resources := []func() resource.Resource{
 		NewStackResource,
 		NewStackComponentResource,
 		NewServiceConnectorResource,
 		NewProjectResource,
 		NewSecretResource,
+		NewSecretValueResource,
+		NewProjectDefaultStackResource,
 	}
+	return append(resources, flavorComponentResources()...)
 }
 
 func (p *ZenMLProvider) DataSources(ctx context.Context) []func() datasource.DataSource {
@@ -278,6 +310,17 @@ func (p *ZenMLProvider) DataSources(ctx context.Context) []func() datasource.Dat
 		NewStackDataSource,
 		NewStackComponentDataSource,
 		NewServiceConnectorDataSource,
+		NewActiveStackDataSource,
+	}
+}
+
+func (p *ZenMLProvider) ListResources(ctx context.Context) []func() list.ListResource {
+	return []func() list.ListResource{
+		NewStackListResource,
+		NewStackComponentListResource,
+		NewServiceConnectorListResource,
+		NewSecretListResource,
+		NewProjectListResource,
 	}
 }
 
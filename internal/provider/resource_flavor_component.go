@@ -0,0 +1,787 @@
+// resource_flavor_component.go
+package provider
+
+//go:generate go run ../../tools/flavorgen -snapshot flavor_schemas.json -output flavor_components_gen.go -docs ../../docs/resources
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/float64default"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// flavorAttributeKind is the Terraform type of a typed flavor configuration
+// attribute.
+type flavorAttributeKind string
+
+const (
+	flavorAttributeString  flavorAttributeKind = "string"
+	flavorAttributeBool    flavorAttributeKind = "bool"
+	flavorAttributeInt64   flavorAttributeKind = "int64"
+	flavorAttributeFloat64 flavorAttributeKind = "float64"
+	// flavorAttributeList is a list of strings.
+	flavorAttributeList flavorAttributeKind = "list"
+	// flavorAttributeMap is a map of strings.
+	flavorAttributeMap flavorAttributeKind = "map"
+	// flavorAttributeJSON is a JSON-encoded string, used for configuration
+	// values without a simpler Terraform equivalent.
+	flavorAttributeJSON flavorAttributeKind = "json"
+)
+
+// flavorAttributeSpec describes a configuration attribute of a typed flavor
+// resource.
+type flavorAttributeSpec struct {
+	// Name is the Terraform attribute name, which differs from Key when the
+	// configuration key clashes with a common stack component attribute.
+	Name        string
+	Key         string
+	Kind        flavorAttributeKind
+	Description string
+	Required    bool
+	Sensitive   bool
+	// Default is the JSON-encoded default value, or empty if the attribute
+	// has no default.
+	Default string
+	// Enum lists the allowed values of string attributes.
+	Enum []string
+}
+
+// flavorComponentSpec describes a typed resource for one stack component
+// flavor. The specs are generated from flavor configuration schemas by
+// tools/flavorgen.
+type flavorComponentSpec struct {
+	TypeName      string
+	ComponentType string
+	Flavor        string
+	Description   string
+	Attributes    []flavorAttributeSpec
+}
+
+// flavorComponentCommonAttributes are the attributes of zenml_stack_component
+// shared by all typed flavor resources.
+var flavorComponentCommonAttributes = []string{
+	"id",
+	"name",
+	"connector_id",
+	"connector_resource_id",
+	"validate_connector_resource",
+	"effective_configuration",
+	"labels",
+	"labels_all",
+	"labels_mode",
+	"created",
+	"updated",
+	"adopt_existing",
+	"deletion_protection",
+}
+
+// flavorComponentResources returns constructors for the typed resources of all
+// generated flavor specs.
+func flavorComponentResources() []func() resource.Resource {
+	constructors := make([]func() resource.Resource, 0, len(flavorComponentSpecs))
+	for _, spec := range flavorComponentSpecs {
+		constructors = append(constructors, newFlavorComponentResource(spec))
+	}
+	return constructors
+}
+
+func newFlavorComponentResource(spec flavorComponentSpec) func() resource.Resource {
+	return func() resource.Resource {
+		r := &FlavorComponentResource{spec: spec}
+		r.component = &StackComponentResource{configCodec: r}
+		return r
+	}
+}
+
+var _ resource.Resource = &FlavorComponentResource{}
+var _ resource.ResourceWithImportState = &FlavorComponentResource{}
+var _ resource.ResourceWithIdentity = &FlavorComponentResource{}
+var _ resource.ResourceWithModifyPlan = &FlavorComponentResource{}
+var _ resource.ResourceWithMoveState = &FlavorComponentResource{}
+
+// FlavorComponentResource is a stack component resource with typed
+// configuration attributes for a single flavor. It translates its state to
+// the zenml_stack_component schema and delegates to StackComponentResource.
+type FlavorComponentResource struct {
+	spec      flavorComponentSpec
+	component *StackComponentResource
+}
+
+func (r *FlavorComponentResource) kind() string {
+	return r.spec.Flavor + " " + strings.ReplaceAll(r.spec.ComponentType, "_", " ")
+}
+
+func (r *FlavorComponentResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_" + r.spec.TypeName
+}
+
+func (r *FlavorComponentResource) IdentitySchema(ctx context.Context, req resource.IdentitySchemaRequest, resp *resource.IdentitySchemaResponse) {
+	resp.IdentitySchema = resourceIdentitySchema(r.kind())
+}
+
+func (r *FlavorComponentResource) componentSchema(ctx context.Context) schema.Schema {
+	resp := &resource.SchemaResponse{}
+	r.component.Schema(ctx, resource.SchemaRequest{}, resp)
+	return resp.Schema
+}
+
+func (r *FlavorComponentResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	componentSchema := r.componentSchema(ctx)
+
+	attributes := make(map[string]schema.Attribute, len(flavorComponentCommonAttributes)+len(r.spec.Attributes))
+	for _, name := range flavorComponentCommonAttributes {
+		attributes[name] = componentSchema.Attributes[name]
+	}
+
+	connectorResourceID := attributes["connector_resource_id"].(schema.StringAttribute)
+	connectorResourceID.Validators = append(connectorResourceID.Validators,
+		stringvalidator.AlsoRequires(path.MatchRoot("connector_id")),
+	)
+	attributes["connector_resource_id"] = connectorResourceID
+
+	for _, spec := range r.spec.Attributes {
+		attributes[spec.Name] = spec.schemaAttribute()
+	}
+
+	resp.Schema = schema.Schema{
+		MarkdownDescription: r.spec.Description,
+		Attributes:          attributes,
+	}
+}
+
+// schemaAttribute returns the Terraform schema of a typed configuration
+// attribute. Optional attributes with a default are also computed, so that
+// the default is planned. Other optional attributes are left unset when they
+// are not configured, so removing them from the configuration unsets them;
+// the values the server fills in for them are shown in
+// effective_configuration.
+func (a flavorAttributeSpec) schemaAttribute() schema.Attribute {
+	optional := !a.Required
+	description := a.Description
+	if a.Kind == flavorAttributeJSON {
+		description = strings.TrimSpace(description + " JSON-encoded.")
+	}
+
+	switch a.Kind {
+	case flavorAttributeBool:
+		attribute := schema.BoolAttribute{
+			MarkdownDescription: description,
+			Required:            a.Required,
+			Optional:            optional,
+			Sensitive:           a.Sensitive,
+		}
+		var value bool
+		if a.Default != "" && json.Unmarshal([]byte(a.Default), &value) == nil {
+			attribute.Computed = true
+			attribute.Default = booldefault.StaticBool(value)
+		}
+		return attribute
+	case flavorAttributeInt64:
+		attribute := schema.Int64Attribute{
+			MarkdownDescription: description,
+			Required:            a.Required,
+			Optional:            optional,
+			Sensitive:           a.Sensitive,
+		}
+		var value int64
+		if a.Default != "" && json.Unmarshal([]byte(a.Default), &value) == nil {
+			attribute.Computed = true
+			attribute.Default = int64default.StaticInt64(value)
+		}
+		return attribute
+	case flavorAttributeFloat64:
+		attribute := schema.Float64Attribute{
+			MarkdownDescription: description,
+			Required:            a.Required,
+			Optional:            optional,
+			Sensitive:           a.Sensitive,
+		}
+		var value float64
+		if a.Default != "" && json.Unmarshal([]byte(a.Default), &value) == nil {
+			attribute.Computed = true
+			attribute.Default = float64default.StaticFloat64(value)
+		}
+		return attribute
+	case flavorAttributeList:
+		attribute := schema.ListAttribute{
+			MarkdownDescription: description,
+			ElementType:         types.StringType,
+			Required:            a.Required,
+			Optional:            optional,
+			Sensitive:           a.Sensitive,
+		}
+		return attribute
+	case flavorAttributeMap:
+		attribute := schema.MapAttribute{
+			MarkdownDescription: description,
+			ElementType:         types.StringType,
+			Required:            a.Required,
+			Optional:            optional,
+			Sensitive:           a.Sensitive,
+		}
+		return attribute
+	default:
+		attribute := schema.StringAttribute{
+			MarkdownDescription: description,
+			Required:            a.Required,
+			Optional:            optional,
+			Sensitive:           a.Sensitive,
+		}
+		var value string
+		if a.Kind == flavorAttributeString && a.Default != "" && json.Unmarshal([]byte(a.Default), &value) == nil {
+			attribute.Computed = true
+			attribute.Default = stringdefault.StaticString(value)
+		}
+		if a.Kind == flavorAttributeJSON {
+			attribute.Validators = []validator.String{jsonStringValidator{}}
+		}
+		if len(a.Enum) > 0 {
+			attribute.Validators = append(attribute.Validators, stringvalidator.OneOf(a.Enum...))
+		}
+		return attribute
+	}
+}
+
+func (r *FlavorComponentResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	r.component.Configure(ctx, req, resp)
+}
+
+func (r *FlavorComponentResource) attributeByKey(key string) (flavorAttributeSpec, bool) {
+	for _, a := range r.spec.Attributes {
+		if a.Key == key {
+			return a, true
+		}
+	}
+	return flavorAttributeSpec{}, false
+}
+
+// encodeConfigValue converts the string form of a configuration value to the
+// value sent to the ZenML API.
+func (r *FlavorComponentResource) encodeConfigValue(key, value string) (interface{}, error) {
+	a, ok := r.attributeByKey(key)
+	if !ok {
+		return value, nil
+	}
+
+	switch a.Kind {
+	case flavorAttributeBool:
+		return strconv.ParseBool(value)
+	case flavorAttributeInt64:
+		return strconv.ParseInt(value, 10, 64)
+	case flavorAttributeFloat64:
+		return strconv.ParseFloat(value, 64)
+	case flavorAttributeList, flavorAttributeMap, flavorAttributeJSON:
+		var decoded interface{}
+		if err := json.Unmarshal([]byte(value), &decoded); err != nil {
+			return nil, err
+		}
+		return decoded, nil
+	default:
+		return value, nil
+	}
+}
+
+// decodeConfigValue converts a configuration value returned by the ZenML API
+// to its string form. Unset values and keys without a typed attribute are
+// left out.
+func (r *FlavorComponentResource) decodeConfigValue(key string, value interface{}) (string, bool) {
+	a, ok := r.attributeByKey(key)
+	if !ok || value == nil {
+		return "", false
+	}
+
+	switch v := value.(type) {
+	case string:
+		if a.Kind == flavorAttributeString || a.Kind == flavorAttributeJSON {
+			return v, true
+		}
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), true
+	case bool:
+		return strconv.FormatBool(v), true
+	}
+
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return "", false
+	}
+	return string(encoded), true
+}
+
+// attributeGetter is implemented by tfsdk.Plan, tfsdk.State and tfsdk.Config.
+type attributeGetter interface {
+	GetAttribute(ctx context.Context, p path.Path, target interface{}) diag.Diagnostics
+}
+
+// componentModel converts typed resource data to the zenml_stack_component
+// model. Configuration attributes that are null or unknown are left out.
+func (r *FlavorComponentResource) componentModel(
+	ctx context.Context,
+	src attributeGetter,
+	diags *diag.Diagnostics,
+) StackComponentResourceModel {
+	data := StackComponentResourceModel{
+		Type:   types.StringValue(r.spec.ComponentType),
+		Flavor: types.StringValue(r.spec.Flavor),
+		// Sensitive configuration attributes are marked sensitive in the
+		// typed schema itself.
+		SecretConfiguration: types.MapNull(types.StringType),
+	}
+	diags.Append(src.GetAttribute(ctx, path.Root("id"), &data.ID)...)
+	diags.Append(src.GetAttribute(ctx, path.Root("name"), &data.Name)...)
+	diags.Append(src.GetAttribute(ctx, path.Root("connector_id"), &data.ConnectorID)...)
+	diags.Append(src.GetAttribute(ctx, path.Root("connector_resource_id"), &data.ConnectorResourceID)...)
+	diags.Append(src.GetAttribute(ctx, path.Root("validate_connector_resource"), &data.ValidateConnectorResource)...)
+	diags.Append(src.GetAttribute(ctx, path.Root("effective_configuration"), &data.EffectiveConfiguration)...)
+	diags.Append(src.GetAttribute(ctx, path.Root("labels"), &data.Labels)...)
+	diags.Append(src.GetAttribute(ctx, path.Root("labels_all"), &data.LabelsAll)...)
+	diags.Append(src.GetAttribute(ctx, path.Root("labels_mode"), &data.LabelsMode)...)
+	diags.Append(src.GetAttribute(ctx, path.Root("created"), &data.Created)...)
+	diags.Append(src.GetAttribute(ctx, path.Root("updated"), &data.Updated)...)
+	diags.Append(src.GetAttribute(ctx, path.Root("adopt_existing"), &data.AdoptExisting)...)
+	diags.Append(src.GetAttribute(ctx, path.Root("deletion_protection"), &data.DeletionProtection)...)
+	if diags.HasError() {
+		return data
+	}
+
+	configuration := make(map[string]attr.Value, len(r.spec.Attributes))
+	for _, a := range r.spec.Attributes {
+		if value, ok := a.stringValue(ctx, src, diags); ok {
+			configuration[a.Key] = types.StringValue(value)
+		}
+	}
+	if diags.HasError() {
+		return data
+	}
+
+	data.Configuration = types.MapNull(types.StringType)
+	if len(configuration) > 0 {
+		value, valueDiags := types.MapValue(types.StringType, configuration)
+		diags.Append(valueDiags...)
+		data.Configuration = value
+	}
+	return data
+}
+
+// stringValue returns the string form of a typed configuration attribute, or
+// false if the attribute is null or unknown.
+func (a flavorAttributeSpec) stringValue(ctx context.Context, src attributeGetter, diags *diag.Diagnostics) (string, bool) {
+	p := path.Root(a.Name)
+
+	switch a.Kind {
+	case flavorAttributeBool:
+		var v types.Bool
+		diags.Append(src.GetAttribute(ctx, p, &v)...)
+		if v.IsNull() || v.IsUnknown() {
+			return "", false
+		}
+		return strconv.FormatBool(v.ValueBool()), true
+	case flavorAttributeInt64:
+		var v types.Int64
+		diags.Append(src.GetAttribute(ctx, p, &v)...)
+		if v.IsNull() || v.IsUnknown() {
+			return "", false
+		}
+		return strconv.FormatInt(v.ValueInt64(), 10), true
+	case flavorAttributeFloat64:
+		var v types.Float64
+		diags.Append(src.GetAttribute(ctx, p, &v)...)
+		if v.IsNull() || v.IsUnknown() {
+			return "", false
+		}
+		return strconv.FormatFloat(v.ValueFloat64(), 'f', -1, 64), true
+	case flavorAttributeList:
+		var v types.List
+		diags.Append(src.GetAttribute(ctx, p, &v)...)
+		if v.IsNull() || v.IsUnknown() {
+			return "", false
+		}
+		var elements []string
+		diags.Append(v.ElementsAs(ctx, &elements, false)...)
+		encoded, _ := json.Marshal(elements)
+		return string(encoded), !diags.HasError()
+	case flavorAttributeMap:
+		var v types.Map
+		diags.Append(src.GetAttribute(ctx, p, &v)...)
+		if v.IsNull() || v.IsUnknown() {
+			return "", false
+		}
+		var elements map[string]string
+		diags.Append(v.ElementsAs(ctx, &elements, false)...)
+		encoded, _ := json.Marshal(elements)
+		return string(encoded), !diags.HasError()
+	default:
+		var v types.String
+		diags.Append(src.GetAttribute(ctx, p, &v)...)
+		if v.IsNull() || v.IsUnknown() {
+			return "", false
+		}
+		return v.ValueString(), true
+	}
+}
+
+// typedValue parses the string form of a configuration value.
+func (a flavorAttributeSpec) typedValue(ctx context.Context, value string) (attr.Value, error) {
+	switch a.Kind {
+	case flavorAttributeBool:
+		v, err := strconv.ParseBool(value)
+		return types.BoolValue(v), err
+	case flavorAttributeInt64:
+		v, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			// Whole numbers may be returned by the server as floats.
+			f, floatErr := strconv.ParseFloat(value, 64)
+			if floatErr != nil || f != float64(int64(f)) {
+				return nil, err
+			}
+			v = int64(f)
+		}
+		return types.Int64Value(v), nil
+	case flavorAttributeFloat64:
+		v, err := strconv.ParseFloat(value, 64)
+		return types.Float64Value(v), err
+	case flavorAttributeList:
+		var elements []string
+		if err := json.Unmarshal([]byte(value), &elements); err != nil {
+			return nil, err
+		}
+		v, diags := types.ListValueFrom(ctx, types.StringType, elements)
+		if diags.HasError() {
+			return nil, fmt.Errorf("invalid list value %s", value)
+		}
+		return v, nil
+	case flavorAttributeMap:
+		var elements map[string]string
+		if err := json.Unmarshal([]byte(value), &elements); err != nil {
+			return nil, err
+		}
+		v, diags := types.MapValueFrom(ctx, types.StringType, elements)
+		if diags.HasError() {
+			return nil, fmt.Errorf("invalid map value %s", value)
+		}
+		return v, nil
+	default:
+		return types.StringValue(value), nil
+	}
+}
+
+// setTypedState converts zenml_stack_component data to the state of the typed
+// resource.
+func (r *FlavorComponentResource) setTypedState(
+	ctx context.Context,
+	data StackComponentResourceModel,
+	state *tfsdk.State,
+	diags *diag.Diagnostics,
+) {
+	state.Raw = nullAttributesValue(state.Schema.Type().TerraformType(ctx))
+
+	diags.Append(state.SetAttribute(ctx, path.Root("id"), data.ID)...)
+	diags.Append(state.SetAttribute(ctx, path.Root("name"), data.Name)...)
+	diags.Append(state.SetAttribute(ctx, path.Root("connector_id"), data.ConnectorID)...)
+	diags.Append(state.SetAttribute(ctx, path.Root("connector_resource_id"), data.ConnectorResourceID)...)
+	diags.Append(state.SetAttribute(ctx, path.Root("validate_connector_resource"), data.ValidateConnectorResource)...)
+	diags.Append(state.SetAttribute(ctx, path.Root("effective_configuration"), data.EffectiveConfiguration)...)
+	diags.Append(state.SetAttribute(ctx, path.Root("labels"), data.Labels)...)
+	diags.Append(state.SetAttribute(ctx, path.Root("labels_all"), data.LabelsAll)...)
+	diags.Append(state.SetAttribute(ctx, path.Root("labels_mode"), data.LabelsMode)...)
+	diags.Append(state.SetAttribute(ctx, path.Root("created"), data.Created)...)
+	diags.Append(state.SetAttribute(ctx, path.Root("updated"), data.Updated)...)
+	diags.Append(state.SetAttribute(ctx, path.Root("adopt_existing"), data.AdoptExisting)...)
+	diags.Append(state.SetAttribute(ctx, path.Root("deletion_protection"), data.DeletionProtection)...)
+
+	configuration := make(map[string]string)
+	if !data.Configuration.IsNull() && !data.Configuration.IsUnknown() {
+		diags.Append(data.Configuration.ElementsAs(ctx, &configuration, false)...)
+	}
+	if diags.HasError() {
+		return
+	}
+
+	for _, a := range r.spec.Attributes {
+		value, ok := configuration[a.Key]
+		if !ok {
+			continue
+		}
+		typed, err := a.typedValue(ctx, value)
+		if err != nil {
+			diags.AddAttributeError(
+				path.Root(a.Name),
+				"Invalid Configuration Value",
+				fmt.Sprintf("Unable to convert configuration value %q of the %s: %s", a.Key, r.kind(), err),
+			)
+			continue
+		}
+		diags.Append(state.SetAttribute(ctx, path.Root(a.Name), typed)...)
+	}
+}
+
+func (r *FlavorComponentResource) componentPlan(ctx context.Context, src attributeGetter, diags *diag.Diagnostics) tfsdk.Plan {
+	data := r.componentModel(ctx, src, diags)
+	plan := tfsdk.Plan{Schema: r.componentSchema(ctx)}
+	if !diags.HasError() {
+		diags.Append(plan.Set(ctx, &data)...)
+	}
+	return plan
+}
+
+func (r *FlavorComponentResource) componentState(ctx context.Context, src attributeGetter, diags *diag.Diagnostics) tfsdk.State {
+	data := r.componentModel(ctx, src, diags)
+	state := tfsdk.State{Schema: r.componentSchema(ctx)}
+	if !diags.HasError() {
+		diags.Append(state.Set(ctx, &data)...)
+	}
+	return state
+}
+
+// setStateFromComponent converts a zenml_stack_component state to the state of
+// the typed resource, making sure the component has the expected flavor.
+func (r *FlavorComponentResource) setStateFromComponent(
+	ctx context.Context,
+	componentState tfsdk.State,
+	state *tfsdk.State,
+	diags *diag.Diagnostics,
+) {
+	var data StackComponentResourceModel
+	diags.Append(componentState.Get(ctx, &data)...)
+	if diags.HasError() {
+		return
+	}
+
+	if data.Type.ValueString() != r.spec.ComponentType || data.Flavor.ValueString() != r.spec.Flavor {
+		diags.AddError(
+			"Unexpected Stack Component Flavor",
+			fmt.Sprintf(
+				"Stack component %s is a %s %s, not a %s. Use the "+
+					"zenml_stack_component resource or the typed resource of its flavor instead.",
+				data.Name.ValueString(), data.Flavor.ValueString(),
+				strings.ReplaceAll(data.Type.ValueString(), "_", " "), r.kind(),
+			),
+		)
+		return
+	}
+
+	r.setTypedState(ctx, data, state, diags)
+}
+
+func (r *FlavorComponentResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	plan := r.componentPlan(ctx, req.Plan, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	componentResp := &resource.CreateResponse{
+		State:    tfsdk.State{Schema: plan.Schema},
+		Identity: resp.Identity,
+		Private:  resp.Private,
+	}
+	r.component.Create(ctx, resource.CreateRequest{Plan: plan, ProviderMeta: req.ProviderMeta}, componentResp)
+	resp.Diagnostics.Append(componentResp.Diagnostics...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.setStateFromComponent(ctx, componentResp.State, &resp.State, &resp.Diagnostics)
+}
+
+func (r *FlavorComponentResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	state := r.componentState(ctx, req.State, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	componentResp := &resource.ReadResponse{
+		State:    state,
+		Identity: resp.Identity,
+		Private:  resp.Private,
+	}
+	r.component.Read(ctx, resource.ReadRequest{
+		State:        state,
+		Identity:     req.Identity,
+		Private:      req.Private,
+		ProviderMeta: req.ProviderMeta,
+	}, componentResp)
+	resp.Diagnostics.Append(componentResp.Diagnostics...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if componentResp.State.Raw.IsNull() {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	r.setStateFromComponent(ctx, componentResp.State, &resp.State, &resp.Diagnostics)
+}
+
+func (r *FlavorComponentResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	plan := r.componentPlan(ctx, req.Plan, &resp.Diagnostics)
+	state := r.componentState(ctx, req.State, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	componentResp := &resource.UpdateResponse{
+		State:    tfsdk.State{Schema: plan.Schema},
+		Identity: resp.Identity,
+		Private:  resp.Private,
+	}
+	r.component.Update(ctx, resource.UpdateRequest{
+		Plan:         plan,
+		State:        state,
+		Identity:     req.Identity,
+		Private:      req.Private,
+		ProviderMeta: req.ProviderMeta,
+	}, componentResp)
+	resp.Diagnostics.Append(componentResp.Diagnostics...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.setStateFromComponent(ctx, componentResp.State, &resp.State, &resp.Diagnostics)
+}
+
+func (r *FlavorComponentResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	state := r.componentState(ctx, req.State, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	componentResp := &resource.DeleteResponse{State: state}
+	r.component.Delete(ctx, resource.DeleteRequest{
+		State:        state,
+		Identity:     req.Identity,
+		Private:      req.Private,
+		ProviderMeta: req.ProviderMeta,
+	}, componentResp)
+	resp.Diagnostics.Append(componentResp.Diagnostics...)
+}
+
+func (r *FlavorComponentResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Typed resources are never replaced, as their type and flavor are fixed
+	// and connector changes are applied in place.
+	checkDeletionProtection(ctx, req, resp, r.kind(), "name", false)
+	if resp.Diagnostics.HasError() || req.Plan.Raw.IsNull() {
+		return
+	}
+
+	if planLabelsAll(ctx, r.component.client, req, resp) {
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("updated"), types.StringUnknown())...)
+	}
+
+	var validate types.Bool
+	var connectorID, connectorResourceID types.String
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("validate_connector_resource"), &validate)...)
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("connector_id"), &connectorID)...)
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("connector_resource_id"), &connectorResourceID)...)
+	if resp.Diagnostics.HasError() || !validate.ValueBool() {
+		return
+	}
+	r.component.validateConnectorResource(ctx, r.spec.ComponentType, r.spec.Flavor,
+		connectorID, connectorResourceID, &resp.Diagnostics)
+}
+
+func (r *FlavorComponentResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	if !importStateFromIdentity(ctx, r.component.client, r.kind(), req, resp) {
+		importStateByNameOrID(ctx, req, resp, r.kind(),
+			map[string]string{
+				"name":   req.ID,
+				"type":   r.spec.ComponentType,
+				"flavor": r.spec.Flavor,
+			},
+			r.component.client.ListStackComponents,
+			func(c ComponentResponse) string { return c.ID },
+		)
+	}
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("deletion_protection"), false)...)
+	markImported(ctx, resp)
+}
+
+func (r *FlavorComponentResource) MoveState(ctx context.Context) []resource.StateMover {
+	return []resource.StateMover{
+		{StateMover: r.moveFromStackComponent},
+	}
+}
+
+// moveFromStackComponent moves the state of a zenml_stack_component of the
+// same flavor to the typed resource, converting its configuration map to
+// typed attributes. The source state is decoded by hand, so that states of
+// every schema version of zenml_stack_component can be moved.
+func (r *FlavorComponentResource) moveFromStackComponent(ctx context.Context, req resource.MoveStateRequest, resp *resource.MoveStateResponse) {
+	if req.SourceTypeName != "zenml_stack_component" || !strings.HasSuffix(req.SourceProviderAddress, "/zenml") {
+		return
+	}
+
+	componentSchema := r.componentSchema(ctx)
+	if req.SourceSchemaVersion > componentSchema.Version {
+		resp.Diagnostics.AddError(
+			"Unable to Move Resource State",
+			fmt.Sprintf(
+				"The zenml_stack_component state has schema version %d, which is "+
+					"newer than this provider supports. Upgrade the provider.",
+				req.SourceSchemaVersion,
+			),
+		)
+		return
+	}
+
+	upgradeResp := &resource.UpgradeStateResponse{State: tfsdk.State{Schema: componentSchema}}
+	if upgrader, ok := r.component.UpgradeState(ctx)[req.SourceSchemaVersion]; ok {
+		upgrader.StateUpgrader(ctx, resource.UpgradeStateRequest{RawState: req.SourceRawState}, upgradeResp)
+	} else {
+		upgradeRawState(ctx, req.SourceRawState, &upgradeResp.State, nil, &upgradeResp.Diagnostics)
+	}
+	resp.Diagnostics.Append(upgradeResp.Diagnostics...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.setStateFromComponent(ctx, upgradeResp.State, &resp.TargetState, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if r.component.client != nil {
+		var id types.String
+		resp.Diagnostics.Append(resp.TargetState.GetAttribute(ctx, path.Root("id"), &id)...)
+		setResourceIdentity(ctx, r.component.client, id.ValueString(), resp.TargetIdentity, &resp.Diagnostics)
+	}
+}
+
+// jsonStringValidator validates that a string attribute holds valid JSON.
+type jsonStringValidator struct{}
+
+func (v jsonStringValidator) Description(ctx context.Context) string {
+	return "value must be valid JSON"
+}
+
+func (v jsonStringValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v jsonStringValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+	if !json.Valid([]byte(req.ConfigValue.ValueString())) {
+		resp.Diagnostics.AddAttributeError(
+			req.Path,
+			"Invalid JSON",
+			fmt.Sprintf("The value of %s must be valid JSON, for example produced with jsonencode().", req.Path),
+		)
+	}
+}
@@ -0,0 +1,256 @@
+package provider
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+var testFlavorComponentSpec = flavorComponentSpec{
+	TypeName:      "orchestrator_test",
+	ComponentType: "orchestrator",
+	Flavor:        "test",
+	Attributes: []flavorAttributeSpec{
+		{Name: "config_name", Key: "name", Kind: flavorAttributeString},
+		{Name: "enabled", Key: "enabled", Kind: flavorAttributeBool, Default: "true"},
+		{Name: "parallelism", Key: "parallelism", Kind: flavorAttributeInt64},
+		{Name: "ratio", Key: "ratio", Kind: flavorAttributeFloat64},
+		{Name: "zones", Key: "zones", Kind: flavorAttributeList},
+		{Name: "selectors", Key: "selectors", Kind: flavorAttributeMap},
+		{Name: "settings", Key: "settings", Kind: flavorAttributeJSON},
+		{Name: "token", Key: "token", Kind: flavorAttributeString, Sensitive: true},
+	},
+}
+
+func testFlavorComponentResource(t *testing.T, spec flavorComponentSpec) (*FlavorComponentResource, schema.Schema) {
+	t.Helper()
+
+	r := newFlavorComponentResource(spec)().(*FlavorComponentResource)
+	schemaResp := &resource.SchemaResponse{}
+	r.Schema(context.Background(), resource.SchemaRequest{}, schemaResp)
+	if schemaResp.Diagnostics.HasError() {
+		t.Fatalf("unexpected schema diagnostics: %v", schemaResp.Diagnostics)
+	}
+	return r, schemaResp.Schema
+}
+
+func TestFlavorComponent_Schema(t *testing.T) {
+	_, s := testFlavorComponentResource(t, testFlavorComponentSpec)
+
+	if _, ok := s.Attributes["type"]; ok {
+		t.Errorf("expected typed resources to have no type attribute")
+	}
+	if _, ok := s.Attributes["configuration"]; ok {
+		t.Errorf("expected typed resources to have no configuration attribute")
+	}
+	if _, ok := s.Attributes["config_name"].(schema.StringAttribute); !ok {
+		t.Errorf("expected the name configuration key to use the config_name attribute")
+	}
+	if a := s.Attributes["enabled"].(schema.BoolAttribute); a.Default == nil || !a.Computed {
+		t.Errorf("expected enabled to be computed with a default, got %+v", a)
+	}
+	if a, ok := s.Attributes["parallelism"].(schema.Int64Attribute); !ok || a.Computed {
+		t.Errorf("expected parallelism to be an optional, not computed, int64 attribute")
+	}
+	if a, ok := s.Attributes["zones"].(schema.ListAttribute); !ok || a.Computed {
+		t.Errorf("expected zones to be an optional, not computed, list attribute")
+	}
+	if a := s.Attributes["token"].(schema.StringAttribute); !a.Sensitive {
+		t.Errorf("expected token to be sensitive")
+	}
+}
+
+func TestFlavorComponent_EnumJSONValidators(t *testing.T) {
+	a := flavorAttributeSpec{Name: "mode", Key: "mode", Kind: flavorAttributeJSON, Enum: []string{`"a"`, `"b"`}}
+
+	attribute := a.schemaAttribute().(schema.StringAttribute)
+	if len(attribute.Validators) != 2 {
+		t.Errorf("expected the JSON and enum validators, got %d validators", len(attribute.Validators))
+	}
+}
+
+func TestFlavorComponent_ConfigurationRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	r, s := testFlavorComponentResource(t, testFlavorComponentSpec)
+
+	configuration, diags := types.MapValueFrom(ctx, types.StringType, map[string]string{
+		"name":        "nightly",
+		"enabled":     "false",
+		"parallelism": "4",
+		"ratio":       "0.5",
+		"zones":       `["a","b"]`,
+		"selectors":   `{"pool":"gpu"}`,
+		"settings":    `{"retries":3}`,
+		"unknown":     "ignored",
+	})
+	if diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+
+	state := tfsdk.State{Schema: s}
+	r.setTypedState(ctx, StackComponentResourceModel{
+		ID:                     types.StringValue("component-1"),
+		Name:                   types.StringValue("k8s"),
+		Configuration:          configuration,
+		Labels:                 types.MapNull(types.StringType),
+		LabelsAll:              types.MapNull(types.StringType),
+		EffectiveConfiguration: types.MapNull(types.StringType),
+		DeletionProtection:     types.BoolValue(false),
+	}, &state, &diags)
+	if diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+
+	var parallelism types.Int64
+	diags.Append(state.GetAttribute(ctx, path.Root("parallelism"), &parallelism)...)
+	var token types.String
+	diags.Append(state.GetAttribute(ctx, path.Root("token"), &token)...)
+	if parallelism.ValueInt64() != 4 || !token.IsNull() {
+		t.Errorf("expected typed values, got parallelism %s and token %s", parallelism, token)
+	}
+
+	data := r.componentModel(ctx, state, &diags)
+	if diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+	if data.Type.ValueString() != "orchestrator" || data.Flavor.ValueString() != "test" {
+		t.Errorf("expected type and flavor of the spec, got %s and %s", data.Type, data.Flavor)
+	}
+
+	var got map[string]string
+	diags.Append(data.Configuration.ElementsAs(ctx, &got, false)...)
+	if diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+	want := map[string]string{
+		"name":        "nightly",
+		"enabled":     "false",
+		"parallelism": "4",
+		"ratio":       "0.5",
+		"zones":       `["a","b"]`,
+		"selectors":   `{"pool":"gpu"}`,
+		"settings":    `{"retries":3}`,
+	}
+	if len(got) != len(want) {
+		t.Errorf("expected configuration %v, got %v", want, got)
+	}
+	for k, v := range want {
+		if got[k] != v {
+			t.Errorf("expected configuration %s = %q, got %q", k, v, got[k])
+		}
+	}
+}
+
+func TestFlavorComponent_ConfigCodec(t *testing.T) {
+	r, _ := testFlavorComponentResource(t, testFlavorComponentSpec)
+
+	encoded, err := r.encodeConfigValue("parallelism", "4")
+	if err != nil || encoded != int64(4) {
+		t.Errorf("expected parallelism to be encoded as an integer, got %v (%v)", encoded, err)
+	}
+	encoded, err = r.encodeConfigValue("zones", `["a"]`)
+	if zones, ok := encoded.([]interface{}); err != nil || !ok || len(zones) != 1 {
+		t.Errorf("expected zones to be encoded as a list, got %v (%v)", encoded, err)
+	}
+	if _, err := r.encodeConfigValue("enabled", "yes please"); err == nil {
+		t.Errorf("expected an error for an invalid bool value")
+	}
+
+	if decoded, ok := r.decodeConfigValue("parallelism", float64(4)); !ok || decoded != "4" {
+		t.Errorf("expected parallelism to be decoded as 4, got %q", decoded)
+	}
+	if decoded, ok := r.decodeConfigValue("selectors", map[string]interface{}{"pool": "gpu"}); !ok || decoded != `{"pool":"gpu"}` {
+		t.Errorf("expected selectors to be decoded as JSON, got %q", decoded)
+	}
+	if _, ok := r.decodeConfigValue("unknown", "value"); ok {
+		t.Errorf("expected keys without a typed attribute to be left out")
+	}
+	if _, ok := r.decodeConfigValue("token", nil); ok {
+		t.Errorf("expected unset values to be left out")
+	}
+}
+
+func TestFlavorComponent_MoveState(t *testing.T) {
+	ctx := context.Background()
+
+	var spec flavorComponentSpec
+	for _, s := range flavorComponentSpecs {
+		if s.TypeName == "artifact_store_gcp" {
+			spec = s
+		}
+	}
+	r, s := testFlavorComponentResource(t, spec)
+
+	fixture, err := os.ReadFile(filepath.Join("testdata", "state_upgrade", "zenml_stack_component_v0.json"))
+	if err != nil {
+		t.Fatalf("missing state fixture: %s", err)
+	}
+
+	resp := &resource.MoveStateResponse{
+		TargetState: tfsdk.State{Schema: s, Raw: tftypes.NewValue(s.Type().TerraformType(ctx), nil)},
+	}
+	r.moveFromStackComponent(ctx, resource.MoveStateRequest{
+		SourceProviderAddress: "registry.terraform.io/zenml/zenml",
+		SourceTypeName:        "zenml_stack_component",
+		SourceSchemaVersion:   0,
+		SourceRawState:        &tfprotov6.RawState{JSON: fixture},
+	}, resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+	}
+
+	var id, storePath types.String
+	var deletionProtection types.Bool
+	resp.Diagnostics.Append(resp.TargetState.GetAttribute(ctx, path.Root("id"), &id)...)
+	resp.Diagnostics.Append(resp.TargetState.GetAttribute(ctx, path.Root("path"), &storePath)...)
+	resp.Diagnostics.Append(resp.TargetState.GetAttribute(ctx, path.Root("deletion_protection"), &deletionProtection)...)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+	}
+	if id.ValueString() != "0b8f5b0a-2a55-4c4e-8f0e-3c1d9f6e2a11" {
+		t.Errorf("expected id to be kept, got %s", id)
+	}
+	if storePath.ValueString() != "gs://my-bucket/zenml" {
+		t.Errorf("expected path to be moved from the configuration, got %s", storePath)
+	}
+	if deletionProtection.ValueBool() {
+		t.Errorf("expected deletion_protection to default to false")
+	}
+}
+
+func TestFlavorComponent_MoveStateRejectsOtherFlavors(t *testing.T) {
+	ctx := context.Background()
+	r, s := testFlavorComponentResource(t, testFlavorComponentSpec)
+
+	fixture, err := os.ReadFile(filepath.Join("testdata", "state_upgrade", "zenml_stack_component_v0.json"))
+	if err != nil {
+		t.Fatalf("missing state fixture: %s", err)
+	}
+	req := resource.MoveStateRequest{
+		SourceProviderAddress: "registry.terraform.io/zenml/zenml",
+		SourceTypeName:        "zenml_stack_component",
+		SourceRawState:        &tfprotov6.RawState{JSON: fixture},
+	}
+
+	resp := &resource.MoveStateResponse{TargetState: tfsdk.State{Schema: s}}
+	r.moveFromStackComponent(ctx, req, resp)
+	if !resp.Diagnostics.HasError() {
+		t.Errorf("expected an error when moving a component of another flavor")
+	}
+
+	req.SourceTypeName = "zenml_stack"
+	resp = &resource.MoveStateResponse{TargetState: tfsdk.State{Schema: s}}
+	r.moveFromStackComponent(ctx, req, resp)
+	if resp.Diagnostics.HasError() || !resp.TargetState.Raw.IsNull() {
+		t.Errorf("expected other resource types to be left to other movers")
+	}
+}
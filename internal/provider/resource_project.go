@@ -4,11 +4,13 @@ package provider
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/types"
@@ -17,6 +19,9 @@ import (
 
 var _ resource.Resource = &ProjectResource{}
 var _ resource.ResourceWithImportState = &ProjectResource{}
+var _ resource.ResourceWithIdentity = &ProjectResource{}
+var _ resource.ResourceWithModifyPlan = &ProjectResource{}
+var _ resource.ResourceWithUpgradeState = &ProjectResource{}
 
 func NewProjectResource() resource.Resource {
 	return &ProjectResource{}
@@ -27,21 +32,76 @@ type ProjectResource struct {
 }
 
 type ProjectResourceModel struct {
-	ID          types.String `tfsdk:"id"`
-	Name        types.String `tfsdk:"name"`
-	DisplayName types.String `tfsdk:"display_name"`
-	Description types.String `tfsdk:"description"`
-	Created     types.String `tfsdk:"created"`
-	Updated     types.String `tfsdk:"updated"`
+	ID                 types.String `tfsdk:"id"`
+	Name               types.String `tfsdk:"name"`
+	DisplayName        types.String `tfsdk:"display_name"`
+	Description        types.String `tfsdk:"description"`
+	Created            types.String `tfsdk:"created"`
+	Updated            types.String `tfsdk:"updated"`
+	ForceDestroy       types.Bool   `tfsdk:"force_destroy"`
+	DeletionProtection types.Bool   `tfsdk:"deletion_protection"`
+}
+
+// projectInventory lists the objects contained in a project that prevent it
+// from being deleted. Stacks and stack components are shared by all projects
+// of a ZenML server and are therefore not part of the inventory.
+type projectInventory struct {
+	Pipelines []PipelineResponse
+	Runs      []PipelineRunResponse
+}
+
+func (i projectInventory) IsEmpty() bool {
+	return len(i.Pipelines) == 0 && len(i.Runs) == 0
+}
+
+// Summary describes the contents of the project in a human-readable form,
+// listing at most a few names per kind of object.
+func (i projectInventory) Summary() string {
+	const maxNames = 5
+
+	describe := func(kind string, names []string) string {
+		line := fmt.Sprintf("  - %d %s", len(names), kind)
+		if len(names) == 0 {
+			return line
+		}
+		shown := names
+		if len(shown) > maxNames {
+			shown = shown[:maxNames]
+		}
+		line += ": " + strings.Join(shown, ", ")
+		if len(names) > maxNames {
+			line += fmt.Sprintf(", and %d more", len(names)-maxNames)
+		}
+		return line
+	}
+
+	pipelineNames := make([]string, len(i.Pipelines))
+	for idx, p := range i.Pipelines {
+		pipelineNames[idx] = p.Name
+	}
+	runNames := make([]string, len(i.Runs))
+	for idx, r := range i.Runs {
+		runNames[idx] = r.Name
+	}
+
+	return strings.Join([]string{
+		describe("pipeline(s)", pipelineNames),
+		describe("pipeline run(s)", runNames),
+	}, "\n")
 }
 
 func (r *ProjectResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
 	resp.TypeName = req.ProviderTypeName + "_project"
 }
 
+func (r *ProjectResource) IdentitySchema(ctx context.Context, req resource.IdentitySchemaRequest, resp *resource.IdentitySchemaResponse) {
+	resp.IdentitySchema = resourceIdentitySchema("project")
+}
+
 func (r *ProjectResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
 		MarkdownDescription: "Project resource",
+		Version:             1,
 
 		Attributes: map[string]schema.Attribute{
 			"id": schema.StringAttribute{
@@ -71,10 +131,30 @@ func (r *ProjectResource) Schema(ctx context.Context, req resource.SchemaRequest
 				MarkdownDescription: "The timestamp when the project was last updated",
 				Computed:            true,
 			},
+			"force_destroy": schema.BoolAttribute{
+				MarkdownDescription: "Whether to delete all pipelines and pipeline " +
+					"runs contained in the project when the project is " +
+					"deleted. When false, deleting a project that is not " +
+					"empty fails.",
+				Optional: true,
+				Computed: true,
+				Default:  booldefault.StaticBool(false),
+			},
+			"deletion_protection": deletionProtectionAttribute(),
 		},
 	}
 }
 
+func (r *ProjectResource) UpgradeState(ctx context.Context) map[int64]resource.StateUpgrader {
+	return map[int64]resource.StateUpgrader{
+		// Version 0 states predate force_destroy and deletion_protection.
+		0: rawStateUpgrader(
+			setStateDefault("force_destroy", false),
+			setStateDefault("deletion_protection", false),
+		),
+	}
+}
+
 func (r *ProjectResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
 	// Prevent panic if the provider has not been configured.
 	if req.ProviderData == nil {
@@ -154,6 +234,7 @@ func (r *ProjectResource) Create(ctx context.Context, req resource.CreateRequest
 	tflog.Trace(ctx, "created a project")
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	setResourceIdentity(ctx, r.client, data.ID.ValueString(), resp.Identity, &resp.Diagnostics)
 }
 
 func (r *ProjectResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
@@ -161,6 +242,7 @@ func (r *ProjectResource) Read(ctx context.Context, req resource.ReadRequest, re
 
 	// Read Terraform prior state data into the model
 	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	checkResourceIdentity(ctx, r.client, "project", data.ID.ValueString(), req.Identity, &resp.Diagnostics)
 
 	if resp.Diagnostics.HasError() {
 		return
@@ -184,6 +266,7 @@ func (r *ProjectResource) Read(ctx context.Context, req resource.ReadRequest, re
 	}
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	setResourceIdentity(ctx, r.client, data.ID.ValueString(), resp.Identity, &resp.Diagnostics)
 }
 
 func (r *ProjectResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
@@ -226,6 +309,7 @@ func (r *ProjectResource) Update(ctx context.Context, req resource.UpdateRequest
 	}
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	setResourceIdentity(ctx, r.client, data.ID.ValueString(), resp.Identity, &resp.Diagnostics)
 }
 
 func (r *ProjectResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
@@ -237,15 +321,143 @@ func (r *ProjectResource) Delete(ctx context.Context, req resource.DeleteRequest
 		return
 	}
 
+	if isDeletionProtected(data.DeletionProtection) {
+		addDeletionProtectionError(&resp.Diagnostics, "project", data.Name.ValueString(), "delete")
+		return
+	}
+
 	tflog.Trace(ctx, "deleting project")
 
-	err := r.client.DeleteProject(ctx, data.ID.ValueString())
+	inventory, err := r.listProjectInventory(ctx, data.ID.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Client Error",
+			fmt.Sprintf("Unable to list the contents of the project: %s", err),
+		)
+		return
+	}
+
+	if !inventory.IsEmpty() {
+		if !data.ForceDestroy.ValueBool() {
+			resp.Diagnostics.AddError(
+				"Project Not Empty",
+				fmt.Sprintf(
+					"Cannot delete project '%s' because it is not empty. It "+
+						"contains:\n\n%s\n\n"+
+						"Delete these objects first, or set force_destroy = "+
+						"true and apply the change to delete them together "+
+						"with the project.",
+					data.Name.ValueString(),
+					inventory.Summary(),
+				),
+			)
+			return
+		}
+
+		r.deleteProjectInventory(ctx, data.Name.ValueString(), inventory, &resp.Diagnostics)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	err = r.client.DeleteProject(ctx, data.ID.ValueString())
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to delete project, got error: %s", err))
 		return
 	}
 }
 
+func (r *ProjectResource) listProjectInventory(
+	ctx context.Context,
+	projectID string,
+) (*projectInventory, error) {
+	pipelines, err := collectPages(ctx, &ListParams{
+		Filter: map[string]string{"project": projectID},
+	}, r.client.ListPipelines)
+	if err != nil {
+		return nil, err
+	}
+
+	runs, err := collectPages(ctx, &ListParams{
+		Filter: map[string]string{"project": projectID},
+	}, r.client.ListPipelineRuns)
+	if err != nil {
+		return nil, err
+	}
+
+	return &projectInventory{
+		Pipelines: pipelines,
+		Runs:      runs,
+	}, nil
+}
+
+// deleteProjectInventory deletes the contents of a project in dependency
+// order: pipeline runs first, then the pipelines they belong to.
+func (r *ProjectResource) deleteProjectInventory(
+	ctx context.Context,
+	projectName string,
+	inventory *projectInventory,
+	diags *diag.Diagnostics,
+) {
+	tflog.Info(ctx, fmt.Sprintf(
+		"Force destroying project %s: deleting %d pipeline run(s) and %d pipeline(s)",
+		projectName,
+		len(inventory.Runs),
+		len(inventory.Pipelines),
+	))
+
+	for i, run := range inventory.Runs {
+		tflog.Info(ctx, fmt.Sprintf(
+			"Deleting pipeline run %s (%d/%d)", run.Name, i+1, len(inventory.Runs),
+		))
+		if err := r.client.DeletePipelineRun(ctx, run.ID); err != nil {
+			diags.AddError(
+				"Client Error",
+				fmt.Sprintf("Unable to delete pipeline run %s: %s", run.Name, err),
+			)
+			return
+		}
+	}
+
+	for i, pipeline := range inventory.Pipelines {
+		tflog.Info(ctx, fmt.Sprintf(
+			"Deleting pipeline %s (%d/%d)", pipeline.Name, i+1, len(inventory.Pipelines),
+		))
+		if err := r.client.DeletePipeline(ctx, pipeline.ID); err != nil {
+			diags.AddError(
+				"Client Error",
+				fmt.Sprintf("Unable to delete pipeline %s: %s", pipeline.Name, err),
+			)
+			return
+		}
+	}
+}
+
+func (r *ProjectResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	checkDeletionProtection(ctx, req, resp, "project", "name", false)
+}
+
 func (r *ProjectResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+	switch {
+	case importStateFromIdentity(ctx, r.client, "project", req, resp):
+	case isUUID(req.ID):
+		resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+	default:
+		// Project names are unique and accepted by the API in place of IDs.
+		project, err := r.client.GetProject(ctx, req.ID)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Client Error",
+				fmt.Sprintf("Unable to look up project %q for import, got error: %s", req.ID, err),
+			)
+			return
+		}
+		if project == nil {
+			resp.Diagnostics.AddError("Import Error", fmt.Sprintf("no project found matching %q", req.ID))
+			return
+		}
+		resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), project.ID)...)
+	}
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("force_destroy"), false)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("deletion_protection"), false)...)
 }
@@ -0,0 +1,257 @@
+// resource_project_default_stack.go
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+var _ resource.Resource = &ProjectDefaultStackResource{}
+var _ resource.ResourceWithImportState = &ProjectDefaultStackResource{}
+var _ resource.ResourceWithModifyPlan = &ProjectDefaultStackResource{}
+
+func NewProjectDefaultStackResource() resource.Resource {
+	return &ProjectDefaultStackResource{}
+}
+
+type ProjectDefaultStackResource struct {
+	client *Client
+}
+
+type ProjectDefaultStackResourceModel struct {
+	ID                 types.String `tfsdk:"id"`
+	Project            types.String `tfsdk:"project"`
+	StackID            types.String `tfsdk:"stack_id"`
+	DeletionProtection types.Bool   `tfsdk:"deletion_protection"`
+}
+
+func (r *ProjectDefaultStackResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_project_default_stack"
+}
+
+func (r *ProjectDefaultStackResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Sets the default stack of a project. The default " +
+			"stack is the stack that is active for users of the project until " +
+			"they choose another one.",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Identifier of the project",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"project": schema.StringAttribute{
+				MarkdownDescription: "Name or ID of the project",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"stack_id": schema.StringAttribute{
+				MarkdownDescription: "ID of the stack to use as the default stack of the project",
+				Required:            true,
+			},
+			"deletion_protection": deletionProtectionAttribute(),
+		},
+	}
+}
+
+func (r *ProjectDefaultStackResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*Client)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.client = client
+}
+
+func (r *ProjectDefaultStackResource) populateProjectDefaultStackModel(
+	ctx context.Context,
+	project *ProjectResponse,
+	data *ProjectDefaultStackResourceModel,
+	diags *diag.Diagnostics,
+) {
+	data.ID = types.StringValue(project.ID)
+
+	// Keep the project as it was configured, either by name or by ID, unless
+	// it is not known yet, as is the case after an import.
+	if data.Project.IsNull() || data.Project.IsUnknown() {
+		data.Project = types.StringValue(project.ID)
+	}
+
+	if project.Body != nil && project.Body.DefaultStackID != nil {
+		data.StackID = types.StringValue(*project.Body.DefaultStackID)
+	} else {
+		data.StackID = types.StringNull()
+	}
+}
+
+func (r *ProjectDefaultStackResource) setDefaultStack(
+	ctx context.Context,
+	data *ProjectDefaultStackResourceModel,
+	diags *diag.Diagnostics,
+) {
+	stackID := data.StackID.ValueString()
+
+	stack, err := r.client.GetStack(ctx, stackID)
+	if err != nil {
+		diags.AddError("Client Error", fmt.Sprintf("Unable to read stack, got error: %s", err))
+		return
+	}
+	if stack == nil {
+		diags.AddAttributeError(
+			path.Root("stack_id"),
+			"Stack Not Found",
+			fmt.Sprintf("No stack found with ID %q.", stackID),
+		)
+		return
+	}
+
+	project, err := r.client.UpdateProject(ctx, data.Project.ValueString(), ProjectUpdate{
+		DefaultStackID: &stack.ID,
+	})
+	if err != nil {
+		diags.AddError("Client Error", fmt.Sprintf("Unable to set the default stack of the project, got error: %s", err))
+		return
+	}
+
+	r.populateProjectDefaultStackModel(ctx, project, data, diags)
+}
+
+func (r *ProjectDefaultStackResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data ProjectDefaultStackResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Trace(ctx, "setting project default stack")
+
+	r.setDefaultStack(ctx, &data, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Trace(ctx, "set project default stack")
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *ProjectDefaultStackResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data ProjectDefaultStackResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	projectID := data.ID.ValueString()
+	if projectID == "" {
+		projectID = data.Project.ValueString()
+	}
+
+	project, err := r.client.GetProject(ctx, projectID)
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read project, got error: %s", err))
+		return
+	}
+
+	if project == nil {
+		// Project was deleted outside of Terraform
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	r.populateProjectDefaultStackModel(ctx, project, &data, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *ProjectDefaultStackResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data ProjectDefaultStackResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Trace(ctx, "updating project default stack")
+
+	r.setDefaultStack(ctx, &data, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *ProjectDefaultStackResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data ProjectDefaultStackResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if isDeletionProtected(data.DeletionProtection) {
+		addDeletionProtectionError(&resp.Diagnostics, "project default stack", data.Project.ValueString(), "delete")
+		return
+	}
+
+	// A project always has a default stack, so there is nothing to unset on
+	// the server. The current default stack is left in place.
+	tflog.Info(ctx, fmt.Sprintf(
+		"Removing default stack of project %s from Terraform state, the "+
+			"project keeps stack %s as its default stack",
+		data.Project.ValueString(),
+		data.StackID.ValueString(),
+	))
+}
+
+func (r *ProjectDefaultStackResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	replace := false
+	if !req.State.Raw.IsNull() && !req.Plan.Raw.IsNull() {
+		replace = planChangesAttributes(ctx, req, &resp.Diagnostics, path.Root("project"))
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	checkDeletionProtection(ctx, req, resp, "project default stack", "project", replace)
+}
+
+func (r *ProjectDefaultStackResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("project"), req, resp)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("deletion_protection"), false)...)
+}
@@ -0,0 +1,83 @@
+package provider
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccProjectDefaultStack_basic(t *testing.T) {
+	name := "terraform-test-" + acctest.RandString(8)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProjectDefaultStackConfig(name),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair(
+						"zenml_project_default_stack.test", "id",
+						"zenml_project.test", "id",
+					),
+					resource.TestCheckResourceAttrPair(
+						"zenml_project_default_stack.test", "stack_id",
+						"zenml_stack.test", "id",
+					),
+					resource.TestCheckResourceAttrPair(
+						"data.zenml_active_stack.test", "id",
+						"zenml_stack.test", "id",
+					),
+				),
+			},
+			{
+				ResourceName:      "zenml_project_default_stack.test",
+				ImportState:       true,
+				ImportStateId:     name,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func testAccProjectDefaultStackConfig(name string) string {
+	return fmt.Sprintf(`
+%s
+
+resource "zenml_project" "test" {
+  name = %q
+}
+
+resource "zenml_stack_component" "artifact_store" {
+  name   = "%s-store"
+  type   = "artifact_store"
+  flavor = "local"
+}
+
+resource "zenml_stack_component" "orchestrator" {
+  name   = "%s-orchestrator"
+  type   = "orchestrator"
+  flavor = "local"
+}
+
+resource "zenml_stack" "test" {
+  name = "%s-stack"
+
+  components = {
+    artifact_store = zenml_stack_component.artifact_store.id
+    orchestrator   = zenml_stack_component.orchestrator.id
+  }
+}
+
+resource "zenml_project_default_stack" "test" {
+  project  = zenml_project.test.name
+  stack_id = zenml_stack.test.id
+}
+
+data "zenml_active_stack" "test" {
+  project = zenml_project_default_stack.test.project
+}
+`, testAccProviderConfig(), name, name, name, name)
+}
@@ -0,0 +1,40 @@
+package provider
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestProjectInventory_IsEmpty(t *testing.T) {
+	if !(projectInventory{}).IsEmpty() {
+		t.Error("expected inventory without pipelines and runs to be empty")
+	}
+
+	inventory := projectInventory{
+		Runs: []PipelineRunResponse{{ID: "run-1", Name: "training-run"}},
+	}
+	if inventory.IsEmpty() {
+		t.Error("expected inventory with a pipeline run not to be empty")
+	}
+}
+
+func TestProjectInventory_SummaryTruncatesNames(t *testing.T) {
+	inventory := projectInventory{
+		Pipelines: []PipelineResponse{{ID: "pipeline-1", Name: "training"}},
+	}
+	for i := 0; i < 7; i++ {
+		inventory.Runs = append(inventory.Runs, PipelineRunResponse{
+			ID:   "run",
+			Name: "run-" + string(rune('a'+i)),
+		})
+	}
+
+	summary := inventory.Summary()
+
+	if !strings.Contains(summary, "1 pipeline(s): training") {
+		t.Errorf("expected pipeline summary, got:\n%s", summary)
+	}
+	if !strings.Contains(summary, "7 pipeline run(s): run-a, run-b, run-c, run-d, run-e, and 2 more") {
+		t.Errorf("expected truncated run summary, got:\n%s", summary)
+	}
+}
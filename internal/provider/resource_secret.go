@@ -19,6 +19,9 @@ import (
 
 var _ resource.Resource = &SecretResource{}
 var _ resource.ResourceWithImportState = &SecretResource{}
+var _ resource.ResourceWithIdentity = &SecretResource{}
+var _ resource.ResourceWithModifyPlan = &SecretResource{}
+var _ resource.ResourceWithUpgradeState = &SecretResource{}
 
 func NewSecretResource() resource.Resource {
 	return &SecretResource{}
@@ -29,22 +32,28 @@ type SecretResource struct {
 }
 
 type SecretResourceModel struct {
-	ID      types.String `tfsdk:"id"`
-	Name    types.String `tfsdk:"name"`
-	Private types.Bool   `tfsdk:"private"`
-	Values  types.Map    `tfsdk:"values"`
-	UserID  types.String `tfsdk:"user_id"`
-	Created types.String `tfsdk:"created"`
-	Updated types.String `tfsdk:"updated"`
+	ID                 types.String `tfsdk:"id"`
+	Name               types.String `tfsdk:"name"`
+	Private            types.Bool   `tfsdk:"private"`
+	Values             types.Map    `tfsdk:"values"`
+	UserID             types.String `tfsdk:"user_id"`
+	Created            types.String `tfsdk:"created"`
+	Updated            types.String `tfsdk:"updated"`
+	DeletionProtection types.Bool   `tfsdk:"deletion_protection"`
 }
 
 func (r *SecretResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
 	resp.TypeName = req.ProviderTypeName + "_secret"
 }
 
+func (r *SecretResource) IdentitySchema(ctx context.Context, req resource.IdentitySchemaRequest, resp *resource.IdentitySchemaResponse) {
+	resp.IdentitySchema = resourceIdentitySchema("secret")
+}
+
 func (r *SecretResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
 		MarkdownDescription: "Manages a ZenML secret. Secret values are stored in Terraform state; use a secured remote backend.",
+		Version:             1,
 		Attributes: map[string]schema.Attribute{
 			"id": schema.StringAttribute{
 				Computed:            true,
@@ -85,10 +94,18 @@ func (r *SecretResource) Schema(ctx context.Context, req resource.SchemaRequest,
 				Computed:            true,
 				MarkdownDescription: "Timestamp when the secret was last updated",
 			},
+			"deletion_protection": deletionProtectionAttribute(),
 		},
 	}
 }
 
+func (r *SecretResource) UpgradeState(ctx context.Context) map[int64]resource.StateUpgrader {
+	return map[int64]resource.StateUpgrader{
+		// Version 0 states predate deletion_protection.
+		0: rawStateUpgrader(setStateDefault("deletion_protection", false)),
+	}
+}
+
 func (r *SecretResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
 	if req.ProviderData == nil {
 		return
@@ -183,11 +200,13 @@ func (r *SecretResource) Create(ctx context.Context, req resource.CreateRequest,
 		return
 	}
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	setResourceIdentity(ctx, r.client, data.ID.ValueString(), resp.Identity, &resp.Diagnostics)
 }
 
 func (r *SecretResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
 	var data SecretResourceModel
 	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	checkResourceIdentity(ctx, r.client, "secret", data.ID.ValueString(), req.Identity, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
 	}
@@ -209,6 +228,7 @@ func (r *SecretResource) Read(ctx context.Context, req resource.ReadRequest, res
 		return
 	}
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	setResourceIdentity(ctx, r.client, data.ID.ValueString(), resp.Identity, &resp.Diagnostics)
 }
 
 func (r *SecretResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
@@ -239,6 +259,7 @@ func (r *SecretResource) Update(ctx context.Context, req resource.UpdateRequest,
 		return
 	}
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	setResourceIdentity(ctx, r.client, data.ID.ValueString(), resp.Identity, &resp.Diagnostics)
 }
 
 func (r *SecretResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
@@ -248,12 +269,28 @@ func (r *SecretResource) Delete(ctx context.Context, req resource.DeleteRequest,
 		return
 	}
 
+	if isDeletionProtected(data.DeletionProtection) {
+		addDeletionProtectionError(&resp.Diagnostics, "secret", data.Name.ValueString(), "delete")
+		return
+	}
+
 	tflog.Trace(ctx, "deleting secret")
 	if err := r.client.DeleteSecret(ctx, data.ID.ValueString()); err != nil {
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to delete secret, got error: %s", err))
 	}
 }
 
+func (r *SecretResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	checkDeletionProtection(ctx, req, resp, "secret", "name", false)
+}
+
 func (r *SecretResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+	if !importStateFromIdentity(ctx, r.client, "secret", req, resp) {
+		importStateByNameOrID(ctx, req, resp, "secret",
+			map[string]string{"name": req.ID},
+			r.client.ListSecrets,
+			func(s SecretResponse) string { return s.ID },
+		)
+	}
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("deletion_protection"), false)...)
 }
@@ -0,0 +1,308 @@
+// resource_secret_value.go
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+var _ resource.Resource = &SecretValueResource{}
+var _ resource.ResourceWithImportState = &SecretValueResource{}
+var _ resource.ResourceWithModifyPlan = &SecretValueResource{}
+
+func NewSecretValueResource() resource.Resource {
+	return &SecretValueResource{}
+}
+
+type SecretValueResource struct {
+	client *Client
+}
+
+type SecretValueResourceModel struct {
+	ID                 types.String `tfsdk:"id"`
+	Secret             types.String `tfsdk:"secret"`
+	SecretID           types.String `tfsdk:"secret_id"`
+	Key                types.String `tfsdk:"key"`
+	Value              types.String `tfsdk:"value"`
+	DeletionProtection types.Bool   `tfsdk:"deletion_protection"`
+}
+
+func (r *SecretValueResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_secret_value"
+}
+
+func (r *SecretValueResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Manages a single key of an existing ZenML secret. The other keys of " +
+			"the secret are left as they are, so that they can be managed by other means.",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Identifier of the secret value, `<secret_id>/<key>`",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"secret": schema.StringAttribute{
+				MarkdownDescription: "Name or ID of the secret",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"secret_id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "ID of the secret",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"key": schema.StringAttribute{
+				MarkdownDescription: "Key of the value in the secret",
+				Required:            true,
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"value": schema.StringAttribute{
+				MarkdownDescription: "Value stored under the key",
+				Required:            true,
+				Sensitive:           true,
+			},
+			"deletion_protection": deletionProtectionAttribute(),
+		},
+	}
+}
+
+func (r *SecretValueResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*Client)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	r.client = client
+}
+
+// writeSecretValue sets a key of a secret to the given value, or removes the
+// key if value is nil. Only the key is sent to the server, which keeps the
+// other values of the secret, including ones written concurrently by other
+// resources or people.
+func (r *SecretValueResource) writeSecretValue(ctx context.Context, secretID, key string, value *string) error {
+	tflog.Trace(ctx, "writing secret value", map[string]any{"secret_id": secretID, "key": key})
+
+	_, err := r.client.PatchSecretValues(ctx, secretID, map[string]*string{key: value})
+	return err
+}
+
+// resolveSecret returns the ID of the secret of a secret value, which must
+// exist.
+func (r *SecretValueResource) resolveSecret(ctx context.Context, data *SecretValueResourceModel, diags *diag.Diagnostics) string {
+	secretID, err := r.client.ResolveSecretID(ctx, data.Secret.ValueString())
+	if err != nil {
+		diags.AddError("Client Error", fmt.Sprintf("Unable to read secret, got error: %s", err))
+		return ""
+	}
+	if secretID == "" {
+		diags.AddAttributeError(
+			path.Root("secret"),
+			"Secret Not Found",
+			fmt.Sprintf("No secret with name or ID %q exists. Create the secret before managing its values.",
+				data.Secret.ValueString()),
+		)
+	}
+	return secretID
+}
+
+func (r *SecretValueResource) setSecretValue(ctx context.Context, data *SecretValueResourceModel, diags *diag.Diagnostics) {
+	secretID := data.SecretID.ValueString()
+	if data.SecretID.IsUnknown() || secretID == "" {
+		secretID = r.resolveSecret(ctx, data, diags)
+		if diags.HasError() {
+			return
+		}
+	}
+
+	value := data.Value.ValueString()
+	if err := r.writeSecretValue(ctx, secretID, data.Key.ValueString(), &value); err != nil {
+		diags.AddError("Client Error", fmt.Sprintf("Unable to set secret value, got error: %s", err))
+		return
+	}
+
+	data.SecretID = types.StringValue(secretID)
+	data.ID = types.StringValue(secretID + "/" + data.Key.ValueString())
+}
+
+func (r *SecretValueResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data SecretValueResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Trace(ctx, "setting secret value")
+
+	r.setSecretValue(ctx, &data, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Trace(ctx, "set secret value")
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *SecretValueResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data SecretValueResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	secretID := data.SecretID.ValueString()
+	if secretID == "" {
+		// Imported secret values only know the secret by name or ID.
+		secretID = r.resolveSecret(ctx, &data, &resp.Diagnostics)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	secret, err := r.client.GetSecret(ctx, secretID)
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read secret, got error: %s", err))
+		return
+	}
+
+	if secret == nil || secret.Body == nil {
+		// Secret was deleted outside of Terraform
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	value, exists := secret.Body.Values[data.Key.ValueString()]
+	if !exists {
+		// Key was removed outside of Terraform
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if value == nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("value"),
+			"Unable to Read Secret Value",
+			"ZenML did not return the value of this key. Ensure the account configured for the provider can read secret values.",
+		)
+		return
+	}
+
+	data.SecretID = types.StringValue(secret.ID)
+	data.ID = types.StringValue(secret.ID + "/" + data.Key.ValueString())
+	data.Value = types.StringValue(*value)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *SecretValueResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data SecretValueResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Trace(ctx, "updating secret value")
+
+	r.setSecretValue(ctx, &data, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *SecretValueResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data SecretValueResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if isDeletionProtected(data.DeletionProtection) {
+		addDeletionProtectionError(&resp.Diagnostics, "secret value", data.Key.ValueString(), "delete")
+		return
+	}
+
+	secret, err := r.client.GetSecret(ctx, data.SecretID.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read secret, got error: %s", err))
+		return
+	}
+	if secret == nil {
+		// Deleting the secret also removed the key.
+		return
+	}
+
+	if err := r.writeSecretValue(ctx, secret.ID, data.Key.ValueString(), nil); err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to delete secret value, got error: %s", err))
+		return
+	}
+}
+
+func (r *SecretValueResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	replace := false
+	if !req.State.Raw.IsNull() && !req.Plan.Raw.IsNull() {
+		replace = planChangesAttributes(ctx, req, &resp.Diagnostics, path.Root("secret"), path.Root("key"))
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	checkDeletionProtection(ctx, req, resp, "secret value", "key", replace)
+}
+
+// ImportState imports a secret value by `<secret name or ID>/<key>`. The ID is
+// split at the last slash, so keys that contain slashes cannot be imported.
+func (r *SecretValueResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	i := strings.LastIndex(req.ID, "/")
+	if i <= 0 || i == len(req.ID)-1 {
+		resp.Diagnostics.AddError(
+			"Invalid Import ID",
+			fmt.Sprintf("Expected an import ID of the form <secret name or ID>/<key>, got %q.", req.ID),
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("secret"), req.ID[:i])...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("key"), req.ID[i+1:])...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("deletion_protection"), false)...)
+}
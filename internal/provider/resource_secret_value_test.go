@@ -0,0 +1,201 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+/*
+TestAccSecretValue_basic verifies that secret values manage single keys of a
+secret without touching the others:
+1. Create a secret whose values are ignored after creation, and add two keys.
+2. Update one key.
+3. Refresh the secret and check that the key set on creation is still there.
+4. Import a secret value by secret name and key.
+
+It requires the same environment as TestAccSecret_basic.
+*/
+
+func TestAccSecretValue_basic(t *testing.T) {
+	name := "terraform-test-" + acctest.RandString(8)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccSecretPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccSecretValueConfig(name, "first"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("zenml_secret_value.token", "value", "first"),
+					resource.TestCheckResourceAttr("zenml_secret_value.region", "value", "us-east-1"),
+					resource.TestCheckResourceAttrPair(
+						"zenml_secret_value.token", "secret_id", "zenml_secret.test", "id"),
+				),
+			},
+			{
+				Config: testAccSecretValueConfig(name, "second"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("zenml_secret_value.token", "value", "second"),
+				),
+			},
+			{
+				RefreshState: true,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("zenml_secret.test", "values.%", "3"),
+					resource.TestCheckResourceAttr("zenml_secret.test", "values.owner", "platform"),
+					resource.TestCheckResourceAttr("zenml_secret.test", "values.token", "second"),
+				),
+			},
+			{
+				ResourceName:      "zenml_secret_value.token",
+				ImportState:       true,
+				ImportStateId:     name + "/token",
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+// fakeSecretServer serves a single secret whose values are patched on update,
+// like the ZenML API does with patch_values. beforeUpdate, if set, changes the
+// values of the secret before an update is applied, like a concurrent writer.
+func fakeSecretServer(t *testing.T, values map[string]string, beforeUpdate func(map[string]string)) (*httptest.Server, func() map[string]string) {
+	var mu sync.Mutex
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Path != "/api/v1/secrets/secret-1" {
+			http.NotFound(w, req)
+			return
+		}
+		mu.Lock()
+		defer mu.Unlock()
+
+		if req.Method == http.MethodPut {
+			if req.URL.Query().Get("patch_values") != "true" {
+				t.Errorf("expected the values of the secret to be patched, got %s", req.URL)
+			}
+			var patch SecretValuesPatch
+			if err := json.NewDecoder(req.Body).Decode(&patch); err != nil {
+				t.Errorf("unexpected request body: %s", err)
+			}
+			if beforeUpdate != nil {
+				beforeUpdate(values)
+			}
+			for k, v := range patch.Values {
+				if v == nil {
+					delete(values, k)
+				} else {
+					values[k] = *v
+				}
+			}
+		}
+
+		body := &SecretResponseBody{Values: map[string]*string{}}
+		for k, v := range values {
+			body.Values[k] = &v
+		}
+		_ = json.NewEncoder(w).Encode(SecretResponse{ID: "secret-1", Name: "credentials", Body: body})
+	}))
+
+	snapshot := func() map[string]string {
+		mu.Lock()
+		defer mu.Unlock()
+		result := make(map[string]string, len(values))
+		for k, v := range values {
+			result[k] = v
+		}
+		return result
+	}
+	return server, snapshot
+}
+
+func TestSecretValueWrite_ConcurrentKeys(t *testing.T) {
+	server, snapshot := fakeSecretServer(t, map[string]string{"owner": "platform"}, nil)
+	defer server.Close()
+
+	r := &SecretValueResource{client: NewClient(server.URL, "", "test-token")}
+
+	// Terraform applies the secret values of a secret in parallel.
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			value := fmt.Sprintf("value-%d", i)
+			if err := r.writeSecretValue(context.Background(), "secret-1", fmt.Sprintf("key-%d", i), &value); err != nil {
+				t.Errorf("unexpected error: %s", err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	values := snapshot()
+	if len(values) != 11 || values["owner"] != "platform" || values["key-7"] != "value-7" {
+		t.Errorf("expected all keys to be kept, got %v", values)
+	}
+
+	if err := r.writeSecretValue(context.Background(), "secret-1", "key-7", nil); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if _, ok := snapshot()["key-7"]; ok || len(snapshot()) != 10 {
+		t.Errorf("expected only key-7 to be removed, got %v", snapshot())
+	}
+}
+
+func TestSecretValueWrite_KeepsConcurrentChanges(t *testing.T) {
+	// Someone else changes and adds keys of the secret while the value is
+	// written.
+	server, snapshot := fakeSecretServer(t, map[string]string{"owner": "platform"}, func(values map[string]string) {
+		values["owner"] = "data"
+		values["region"] = "eu-west-1"
+	})
+	defer server.Close()
+
+	r := &SecretValueResource{client: NewClient(server.URL, "", "test-token")}
+	value := "token"
+	if err := r.writeSecretValue(context.Background(), "secret-1", "token", &value); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	values := snapshot()
+	if len(values) != 3 || values["owner"] != "data" || values["region"] != "eu-west-1" || values["token"] != "token" {
+		t.Errorf("expected the concurrent changes to be kept, got %v", values)
+	}
+}
+
+func testAccSecretValueConfig(name, token string) string {
+	return fmt.Sprintf(`
+%s
+
+resource "zenml_secret" "test" {
+  name = %q
+
+  values = {
+    owner = "platform"
+  }
+
+  lifecycle {
+    ignore_changes = [values]
+  }
+}
+
+resource "zenml_secret_value" "token" {
+  secret = zenml_secret.test.name
+  key    = "token"
+  value  = %q
+}
+
+resource "zenml_secret_value" "region" {
+  secret = zenml_secret.test.id
+  key    = "region"
+  value  = "us-east-1"
+}
+`, testAccProviderConfig(), name, token)
+}
@@ -5,27 +5,50 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework-validators/mapvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/objectvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/setvalidator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
-	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/setplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/retry"
 )
 
 var _ resource.Resource = &ServiceConnectorResource{}
 var _ resource.ResourceWithImportState = &ServiceConnectorResource{}
+var _ resource.ResourceWithIdentity = &ServiceConnectorResource{}
+var _ resource.ResourceWithModifyPlan = &ServiceConnectorResource{}
 var _ resource.ResourceWithConfigValidators = &ServiceConnectorResource{}
+var _ resource.ResourceWithUpgradeState = &ServiceConnectorResource{}
+
+const (
+	// connectorOnDeleteFail refuses to delete a service connector that is
+	// still used by stack components.
+	connectorOnDeleteFail = "fail"
+	// connectorOnDeleteDetach removes a service connector from the stack
+	// components that use it before deleting it.
+	connectorOnDeleteDetach = "detach"
+)
+
+// connectorRenewingPrivateKey is the planned private state key that marks an
+// update forced by renew_before, which sends the write-only secrets even
+// though their version did not change.
+const connectorRenewingPrivateKey = "renewing"
 
 func NewServiceConnectorResource() resource.Resource {
 	return &ServiceConnectorResource{}
@@ -36,29 +59,66 @@ type ServiceConnectorResource struct {
 }
 
 type ServiceConnectorResourceModel struct {
-	ID            types.String   `tfsdk:"id"`
-	Name          types.String   `tfsdk:"name"`
-	Type          types.String   `tfsdk:"type"`
-	AuthMethod    types.String   `tfsdk:"auth_method"`
-	ResourceType  types.String   `tfsdk:"resource_type"`
-	ResourceID    types.String   `tfsdk:"resource_id"`
-	Configuration types.Map      `tfsdk:"configuration"`
-	Labels        types.Map      `tfsdk:"labels"`
-	ExpiresAt     types.String   `tfsdk:"expires_at"`
-	User          types.String   `tfsdk:"user"`
-	Created       types.String   `tfsdk:"created"`
-	Updated       types.String   `tfsdk:"updated"`
-	Verify        types.Bool     `tfsdk:"verify"`
-	Timeouts      timeouts.Value `tfsdk:"timeouts"`
+	ID                     types.String   `tfsdk:"id"`
+	Name                   types.String   `tfsdk:"name"`
+	Type                   types.String   `tfsdk:"type"`
+	AuthMethod             types.String   `tfsdk:"auth_method"`
+	ResourceType           types.String   `tfsdk:"resource_type"`
+	ResourceTypes          types.Set      `tfsdk:"resource_types"`
+	ResourceID             types.String   `tfsdk:"resource_id"`
+	Configuration          types.Map      `tfsdk:"configuration"`
+	SecretConfiguration    types.Map      `tfsdk:"secret_configuration"`
+	SecretsWO              types.Map      `tfsdk:"secrets_wo"`
+	SecretsWOVersion       types.Int64    `tfsdk:"secrets_wo_version"`
+	EffectiveConfiguration types.Map      `tfsdk:"effective_configuration"`
+	Labels                 types.Map      `tfsdk:"labels"`
+	LabelsAll              types.Map      `tfsdk:"labels_all"`
+	LabelsMode             types.String   `tfsdk:"labels_mode"`
+	ExpiresAt              types.String   `tfsdk:"expires_at"`
+	RenewBefore            types.String   `tfsdk:"renew_before"`
+	User                   types.String   `tfsdk:"user"`
+	Created                types.String   `tfsdk:"created"`
+	Updated                types.String   `tfsdk:"updated"`
+	Verify                 types.Bool     `tfsdk:"verify"`
+	VerifyOnRead           types.Bool     `tfsdk:"verify_on_read"`
+	Resources              types.Map      `tfsdk:"resources"`
+	VerificationError      types.String   `tfsdk:"verification_error"`
+	OnDelete               types.String   `tfsdk:"on_delete"`
+	Timeouts               timeouts.Value `tfsdk:"timeouts"`
+	DeletionProtection     types.Bool     `tfsdk:"deletion_protection"`
+	AutoConfigure          types.Object   `tfsdk:"auto_configure"`
+}
+
+// ServiceConnectorAutoConfigureModel describes the auto_configure block.
+type ServiceConnectorAutoConfigureModel struct {
+	AWSProfile         types.String `tfsdk:"aws_profile"`
+	GCPCredentialsFile types.String `tfsdk:"gcp_credentials_file"`
+	KubernetesContext  types.String `tfsdk:"kubernetes_context"`
+	DockerRegistry     types.String `tfsdk:"docker_registry"`
+	Fingerprint        types.String `tfsdk:"fingerprint"`
+}
+
+func (m ServiceConnectorAutoConfigureModel) options() autoConfigureOptions {
+	return autoConfigureOptions{
+		AWSProfile:         m.AWSProfile.ValueString(),
+		GCPCredentialsFile: m.GCPCredentialsFile.ValueString(),
+		KubernetesContext:  m.KubernetesContext.ValueString(),
+		DockerRegistry:     m.DockerRegistry.ValueString(),
+	}
 }
 
 func (r *ServiceConnectorResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
 	resp.TypeName = req.ProviderTypeName + "_service_connector"
 }
 
+func (r *ServiceConnectorResource) IdentitySchema(ctx context.Context, req resource.IdentitySchemaRequest, resp *resource.IdentitySchemaResponse) {
+	resp.IdentitySchema = resourceIdentitySchema("service connector")
+}
+
 func (r *ServiceConnectorResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
 		MarkdownDescription: "Service connector resource",
+		Version:             1,
 
 		Attributes: map[string]schema.Attribute{
 			"id": schema.StringAttribute{
@@ -99,6 +159,19 @@ func (r *ServiceConnectorResource) Schema(ctx context.Context, req resource.Sche
 					stringplanmodifier.RequiresReplace(),
 				},
 			},
+			"resource_types": schema.SetAttribute{
+				MarkdownDescription: "Resource types the service connector can be used for, for connectors " +
+					"that serve several types of resources. Conflicts with `resource_type`.",
+				ElementType: types.StringType,
+				Optional:    true,
+				Validators: []validator.Set{
+					setvalidator.SizeAtLeast(1),
+					setvalidator.ConflictsWith(path.MatchRoot("resource_type")),
+				},
+				PlanModifiers: []planmodifier.Set{
+					setplanmodifier.RequiresReplace(),
+				},
+			},
 			"resource_id": schema.StringAttribute{
 				MarkdownDescription: "Resource ID for the service connector",
 				Optional:            true,
@@ -111,19 +184,90 @@ func (r *ServiceConnectorResource) Schema(ctx context.Context, req resource.Sche
 				ElementType:         types.StringType,
 				Optional:            true,
 			},
+			"secret_configuration": schema.MapAttribute{
+				MarkdownDescription: "Secret configuration of the service connector, such as " +
+					"`aws_secret_access_key`. The server stores these values as a secret and " +
+					"never returns them, so they are not checked for drift.",
+				ElementType: types.StringType,
+				Optional:    true,
+				Sensitive:   true,
+			},
+			"effective_configuration": schema.MapAttribute{
+				MarkdownDescription: "Complete configuration of the service connector on the server. " +
+					"Only the keys set in `configuration` are checked for drift.",
+				ElementType: types.StringType,
+				Computed:    true,
+			},
+			"secrets_wo": schema.MapAttribute{
+				MarkdownDescription: "Write-only secret configuration of the service connector. " +
+					"The values are sent to the server but never stored in the Terraform plan or state, " +
+					"so they are only sent again when `secrets_wo_version` changes. " +
+					"Requires Terraform 1.11 or later.",
+				ElementType: types.StringType,
+				Optional:    true,
+				Sensitive:   true,
+				WriteOnly:   true,
+				Validators: []validator.Map{
+					mapvalidator.ConflictsWith(path.MatchRoot("secret_configuration")),
+					mapvalidator.AlsoRequires(path.MatchRoot("secrets_wo_version")),
+				},
+			},
+			"secrets_wo_version": schema.Int64Attribute{
+				MarkdownDescription: "Version of the values in `secrets_wo`. Change it to send " +
+					"new values of `secrets_wo` to the server.",
+				Optional: true,
+			},
 			"labels": schema.MapAttribute{
 				MarkdownDescription: "Labels for the service connector",
 				ElementType:         types.StringType,
 				Optional:            true,
 			},
+			"labels_all":  labelsAllAttribute(),
+			"labels_mode": labelsModeAttribute(),
 			"expires_at": schema.StringAttribute{
-				MarkdownDescription: "Expiration time for the service connector (RFC3339 format)",
+				MarkdownDescription: "Expiration time of the service connector credentials, an ISO 8601 timestamp in UTC",
 				Computed:            true,
 			},
+			"renew_before": schema.StringAttribute{
+				MarkdownDescription: "How long before `expires_at` the connector is updated with the " +
+					"current `secrets_wo` values, as a duration such as `30m` or `12h`. Meant for " +
+					"short-lived credentials such as `sts-token`, `session-token` or `oauth2-token`.",
+				Optional: true,
+				Validators: []validator.String{
+					durationValidator{},
+				},
+			},
 			"verify": schema.BoolAttribute{
 				MarkdownDescription: "Whether to verify the service connector configuration before creating or updating it",
 				Optional:            true,
 			},
+			"verify_on_read": schema.BoolAttribute{
+				MarkdownDescription: "Whether to verify the service connector again on every refresh. " +
+					"Failed verifications are reported as warnings and plan an update of the connector, " +
+					"so that expired or revoked credentials show up in `terraform plan`.",
+				Optional: true,
+			},
+			"resources": schema.MapAttribute{
+				MarkdownDescription: "IDs of the resources the service connector can access, by resource type, " +
+					"as of the last verification.",
+				ElementType: types.ListType{ElemType: types.StringType},
+				Computed:    true,
+			},
+			"verification_error": schema.StringAttribute{
+				MarkdownDescription: "Error of the last verification during refresh, if it failed.",
+				Computed:            true,
+			},
+			"on_delete": schema.StringAttribute{
+				MarkdownDescription: "What to do with stack components that still " +
+					"use the service connector when it is deleted. `fail` (the " +
+					"default) refuses to delete the connector and lists the " +
+					"components using it, `detach` removes the connector from " +
+					"those components before deleting it.",
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(connectorOnDeleteFail, connectorOnDeleteDetach),
+				},
+			},
 			"user": schema.StringAttribute{
 				MarkdownDescription: "The owner of the service connector",
 				Computed:            true,
@@ -139,7 +283,48 @@ func (r *ServiceConnectorResource) Schema(ctx context.Context, req resource.Sche
 			"timeouts": timeouts.Attributes(ctx, timeouts.Opts{
 				Create: true,
 				Update: true,
+				Delete: true,
 			}),
+			"deletion_protection": deletionProtectionAttribute(),
+		},
+		Blocks: map[string]schema.Block{
+			"auto_configure": schema.SingleNestedBlock{
+				MarkdownDescription: "Builds the configuration of the service connector from the local " +
+					"credentials of the cloud or tool it connects to, such as `AWS_*` environment variables, " +
+					"`~/.aws/credentials` profiles, `GOOGLE_APPLICATION_CREDENTIALS`, the `AZURE_*` service " +
+					"principal variables, kubeconfig contexts or the docker `config.json`. The values are " +
+					"read on every plan and are never stored in state; keys set in `configuration` or " +
+					"`secret_configuration` take precedence.",
+				Attributes: map[string]schema.Attribute{
+					"aws_profile": schema.StringAttribute{
+						MarkdownDescription: "Profile of the AWS shared credentials file to read. Defaults to " +
+							"the `AWS_*` environment variables, then to the `AWS_PROFILE` or `default` profile.",
+						Optional: true,
+					},
+					"gcp_credentials_file": schema.StringAttribute{
+						MarkdownDescription: "GCP credentials file to read. Defaults to `GOOGLE_APPLICATION_CREDENTIALS`.",
+						Optional:            true,
+					},
+					"kubernetes_context": schema.StringAttribute{
+						MarkdownDescription: "Kubeconfig context to read. Defaults to the current context.",
+						Optional:            true,
+					},
+					"docker_registry": schema.StringAttribute{
+						MarkdownDescription: "Registry of the docker `config.json` to read the credentials of. " +
+							"Can be left out when the file holds the credentials of a single registry.",
+						Optional: true,
+					},
+					"fingerprint": schema.StringAttribute{
+						MarkdownDescription: "Salted digest of the auto-configured values, which changes when " +
+							"the local credentials change.",
+						Computed:  true,
+						Sensitive: true,
+					},
+				},
+				Validators: []validator.Object{
+					objectvalidator.ConflictsWith(path.MatchRoot("secrets_wo")),
+				},
+			},
 		},
 	}
 }
@@ -147,6 +332,7 @@ func (r *ServiceConnectorResource) Schema(ctx context.Context, req resource.Sche
 func (r *ServiceConnectorResource) ConfigValidators(ctx context.Context) []resource.ConfigValidator {
 	return []resource.ConfigValidator{
 		&serviceConnectorConfigValidator{},
+		secretConfigurationValidator{},
 	}
 }
 
@@ -213,21 +399,37 @@ func (v serviceConnectorConfigValidator) ValidateResource(ctx context.Context, r
 	}
 
 	if !data.ResourceType.IsNull() && data.ResourceType.ValueString() != "" {
-		validTypes := validResourceTypes[connectorType]
-		resourceType := data.ResourceType.ValueString()
-		valid := false
-		for _, t := range validTypes {
-			if t == resourceType {
-				valid = true
-				break
+		validateConnectorResourceType(connectorType, data.ResourceType.ValueString(), path.Root("resource_type"), &resp.Diagnostics)
+	}
+
+	if !data.ResourceTypes.IsNull() && !data.ResourceTypes.IsUnknown() {
+		for _, element := range data.ResourceTypes.Elements() {
+			resourceType, ok := element.(types.String)
+			if !ok || resourceType.IsUnknown() || resourceType.IsNull() {
+				continue
 			}
+			validateConnectorResourceType(connectorType, resourceType.ValueString(),
+				path.Root("resource_types").AtSetValue(resourceType), &resp.Diagnostics)
 		}
-		if !valid {
+	}
+
+	// Keys that ZenML stores as secrets would be shown in plans and sent as
+	// plain configuration, so they must be set in secret_configuration.
+	if !data.Configuration.IsNull() && !data.Configuration.IsUnknown() {
+		secretKeys := make([]string, 0)
+		for k := range data.Configuration.Elements() {
+			if connectorSecretKeys[k] {
+				secretKeys = append(secretKeys, k)
+			}
+		}
+		if len(secretKeys) > 0 {
+			sort.Strings(secretKeys)
 			resp.Diagnostics.AddAttributeError(
-				path.Root("resource_type"),
-				"Invalid resource type",
-				fmt.Sprintf("Invalid resource type %q for connector type %q. Valid types are: %s",
-					resourceType, connectorType, strings.Join(validTypes, ", ")),
+				path.Root("configuration"),
+				"Secret Configuration Keys",
+				fmt.Sprintf("The keys %v hold credentials and cannot be set in configuration, "+
+					"where they would be shown in plans. Set them in secret_configuration, "+
+					"or in secrets_wo to keep them out of state.", secretKeys),
 			)
 		}
 	}
@@ -241,6 +443,30 @@ func (v serviceConnectorConfigValidator) ValidateResource(ctx context.Context, r
 	//    duplicate that logic here.
 }
 
+// validateConnectorResourceType checks that a resource type is supported by
+// the connector type.
+func validateConnectorResourceType(connectorType, resourceType string, p path.Path, diags *diag.Diagnostics) {
+	validTypes := validResourceTypes[connectorType]
+	for _, t := range validTypes {
+		if t == resourceType {
+			return
+		}
+	}
+	diags.AddAttributeError(
+		p,
+		"Invalid resource type",
+		fmt.Sprintf("Invalid resource type %q for connector type %q. Valid types are: %s",
+			resourceType, connectorType, strings.Join(validTypes, ", ")),
+	)
+}
+
+func (r *ServiceConnectorResource) UpgradeState(ctx context.Context) map[int64]resource.StateUpgrader {
+	return map[int64]resource.StateUpgrader{
+		// Version 0 states predate on_delete and deletion_protection.
+		0: rawStateUpgrader(setStateDefault("deletion_protection", false)),
+	}
+}
+
 func (r *ServiceConnectorResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
 	// Prevent panic if the provider has not been configured.
 	if req.ProviderData == nil {
@@ -288,22 +514,30 @@ func (r *ServiceConnectorResource) buildServiceConnectorRequest(
 		}
 	}
 
-	var labels map[string]string = make(map[string]string)
-	if !data.Labels.IsNull() && len(data.Labels.Elements()) > 0 {
-		labelElements := make(map[string]types.String, len(data.Labels.Elements()))
-		diags.Append(data.Labels.ElementsAs(ctx, &labelElements, false)...)
+	// Secrets are left out unless secret_configuration is set, so that
+	// secret values configured before it existed are kept on update.
+	var secrets map[string]string
+	if !data.SecretConfiguration.IsNull() {
+		diags.Append(data.SecretConfiguration.ElementsAs(ctx, &secrets, false)...)
 		if diags.HasError() {
 			return nil
 		}
+	}
 
-		for k, v := range labelElements {
-			labels[k] = v.ValueString()
-		}
+	labels := expandLabelsFromTF(ctx, r.client, data.Labels, diags)
+	if diags.HasError() {
+		return nil
 	}
 
 	resourceTypes := []string{}
 	if !data.ResourceType.IsNull() && data.ResourceType.ValueString() != "" {
 		resourceTypes = []string{data.ResourceType.ValueString()}
+	} else if !data.ResourceTypes.IsNull() {
+		diags.Append(data.ResourceTypes.ElementsAs(ctx, &resourceTypes, false)...)
+		if diags.HasError() {
+			return nil
+		}
+		sort.Strings(resourceTypes)
 	}
 
 	connectorReq := &ServiceConnectorRequest{
@@ -313,6 +547,7 @@ func (r *ServiceConnectorResource) buildServiceConnectorRequest(
 		AuthMethod:    data.AuthMethod.ValueString(),
 		ResourceTypes: resourceTypes,
 		Configuration: configuration,
+		Secrets:       secrets,
 		Labels:        labels,
 	}
 
@@ -321,20 +556,73 @@ func (r *ServiceConnectorResource) buildServiceConnectorRequest(
 		connectorReq.ResourceID = &resourceID
 	}
 
+	if !r.applyAutoConfiguration(ctx, data, connectorReq, diags) {
+		return nil
+	}
+
 	return connectorReq
 }
 
+// applyAutoConfiguration adds the values of auto_configure to the request,
+// leaving out keys that are set explicitly, and records their fingerprint if
+// the plan could not compute it. It returns false on errors.
+func (r *ServiceConnectorResource) applyAutoConfiguration(
+	ctx context.Context,
+	data *ServiceConnectorResourceModel,
+	connectorReq *ServiceConnectorRequest,
+	diags *diag.Diagnostics,
+) bool {
+	if data.AutoConfigure.IsNull() || data.AutoConfigure.IsUnknown() {
+		return true
+	}
+
+	var block ServiceConnectorAutoConfigureModel
+	diags.Append(data.AutoConfigure.As(ctx, &block, basetypes.ObjectAsOptions{})...)
+	if diags.HasError() {
+		return false
+	}
+
+	auto, err := autoConfigure(data.Type.ValueString(), data.AuthMethod.ValueString(), block.options())
+	if err != nil {
+		diags.AddAttributeError(path.Root("auto_configure"), "Auto-configuration Error",
+			fmt.Sprintf("Unable to auto-configure service connector, got error: %s", err))
+		return false
+	}
+
+	for k, v := range auto.Configuration {
+		if _, ok := connectorReq.Configuration[k]; !ok {
+			connectorReq.Configuration[k] = v
+		}
+	}
+	for k, v := range auto.Secrets {
+		if connectorReq.Secrets == nil {
+			connectorReq.Secrets = make(map[string]string, len(auto.Secrets))
+		}
+		_, configured := connectorReq.Configuration[k]
+		if _, ok := connectorReq.Secrets[k]; !ok && !configured {
+			connectorReq.Secrets[k] = v
+		}
+	}
+
+	if block.Fingerprint.IsUnknown() {
+		block.Fingerprint = types.StringValue(auto.Fingerprint(data.Type.ValueString(), data.AuthMethod.ValueString(), data.Name.ValueString()))
+		value, objectDiags := types.ObjectValueFrom(ctx, data.AutoConfigure.AttributeTypes(ctx), block)
+		diags.Append(objectDiags...)
+		data.AutoConfigure = value
+	}
+	return !diags.HasError()
+}
+
 func (r *ServiceConnectorResource) populateServiceConnectorModel(
 	ctx context.Context,
 	connector *ServiceConnectorResponse,
 	data *ServiceConnectorResourceModel,
 	diags *diag.Diagnostics,
-	updateConfiguration bool,
+	refresh configurationRefresh,
 ) {
 	data.ID = types.StringValue(connector.ID)
 	data.Name = types.StringValue(connector.Name)
 
-	var oldUpdated string
 	if connector.Body != nil {
 		var connectorType string
 		if err := json.Unmarshal(connector.Body.ConnectorType, &connectorType); err != nil {
@@ -351,7 +639,16 @@ func (r *ServiceConnectorResource) populateServiceConnectorModel(
 
 		data.AuthMethod = types.StringValue(connector.Body.AuthMethod)
 
-		if len(connector.Body.ResourceTypes) == 1 {
+		// Connectors configured with resource_types keep using it, also
+		// when they serve a single type.
+		switch {
+		case !data.ResourceTypes.IsNull() || (refresh == configurationAdopt && len(connector.Body.ResourceTypes) > 1):
+			if refresh != configurationKeep {
+				resourceTypes, setDiags := types.SetValueFrom(ctx, types.StringType, connector.Body.ResourceTypes)
+				diags.Append(setDiags...)
+				data.ResourceTypes = resourceTypes
+			}
+		case len(connector.Body.ResourceTypes) == 1:
 			data.ResourceType = types.StringValue(connector.Body.ResourceTypes[0])
 		}
 
@@ -372,47 +669,233 @@ func (r *ServiceConnectorResource) populateServiceConnectorModel(
 		}
 
 		data.Created = types.StringValue(connector.Body.Created)
-
-		if !data.Updated.IsNull() {
-			oldUpdated = data.Updated.ValueString()
-		}
 		data.Updated = types.StringValue(connector.Body.Updated)
 	}
 
+	data.EffectiveConfiguration = types.MapNull(types.StringType)
 	if connector.Metadata != nil {
-		if len(connector.Metadata.Configuration) > 0 {
-			timestampUnchanged := oldUpdated != "" &&
-				oldUpdated == connector.Body.Updated
-
-			if updateConfiguration && !timestampUnchanged {
-				cfg, changed := MergeOrCompareConfiguration(
-					ctx,
-					data.Configuration,
-					connector.Metadata.Configuration,
-					diags,
-				)
-				if !diags.HasError() && changed {
-					data.Configuration = cfg
-				}
-			}
-		} else if !data.Configuration.IsNull() && len(data.Configuration.Elements()) > 0 {
-			data.Configuration = types.MapNull(types.StringType)
+		data.Configuration = ReconcileConfiguration(ctx, data.Configuration, connector.Metadata.Configuration, connectorSecretKeys, refresh, diags)
+		if refresh == configurationKeep {
+			WarnIgnoredConfiguration(data.Configuration, connector.Metadata.Configuration, diags)
 		}
+		data.EffectiveConfiguration = EffectiveConfiguration(ctx, connector.Metadata.Configuration, diags)
 
-		if len(connector.Metadata.Labels) > 0 {
-			labelMap := make(map[string]attr.Value)
-			for k, v := range connector.Metadata.Labels {
-				labelMap[k] = types.StringValue(v)
-			}
-			labelValue, labelDiags := types.MapValue(types.StringType, labelMap)
-			diags.Append(labelDiags...)
-			if !diags.HasError() {
-				data.Labels = labelValue
-			}
-		} else if !data.Labels.IsNull() && len(data.Labels.Elements()) > 0 {
-			data.Labels = types.MapNull(types.StringType)
+		if labelValue := flattenLabelsToTFMap(r.client, connector.Metadata.Labels, data.Labels, isAdditiveLabels(data.LabelsMode), diags); labelValue != nil {
+			data.Labels = *labelValue
+		}
+		data.LabelsAll = labelsAllValue(connector.Metadata.Labels, diags)
+	}
+}
+
+// connectorUpdateSecrets returns the secrets to send with an update of a
+// connector, or nil to keep the secrets stored on the server. Write-only
+// secrets are only sent when their version changes or the update renews
+// expiring credentials. Other secrets are sent
+// when they are set, and as an empty map, which clears them, when
+// secret_configuration was removed. Connectors that never set
+// secret_configuration keep the secrets they were created with.
+func connectorUpdateSecrets(
+	plan, state *ServiceConnectorResourceModel,
+	secrets, writeOnly map[string]string,
+	renewing bool,
+) *map[string]string {
+	if writeOnly != nil {
+		if !renewing && plan.SecretsWOVersion.Equal(state.SecretsWOVersion) {
+			return nil
+		}
+		return &writeOnly
+	}
+
+	if secrets == nil && plan.SecretConfiguration.Equal(state.SecretConfiguration) {
+		return nil
+	}
+	if secrets == nil {
+		secrets = map[string]string{}
+	}
+	return &secrets
+}
+
+// writeOnlySecrets returns the values of secrets_wo, which are only available
+// in the configuration, or nil if it is not set.
+func writeOnlySecrets(ctx context.Context, config tfsdk.Config, diags *diag.Diagnostics) map[string]string {
+	var value types.Map
+	diags.Append(config.GetAttribute(ctx, path.Root("secrets_wo"), &value)...)
+	if diags.HasError() || value.IsNull() || value.IsUnknown() {
+		return nil
+	}
+
+	secrets := make(map[string]string, len(value.Elements()))
+	diags.Append(value.ElementsAs(ctx, &secrets, false)...)
+	return secrets
+}
+
+// transientVerificationErrors are fragments of verification errors caused by
+// changes that have not propagated yet, such as a freshly created IAM role or
+// a permission granted moments ago, and by temporary outages. They are
+// checked before permanentVerificationErrors, because some of them are
+// reported as permission errors.
+var transientVerificationErrors = []string{
+	"sts:assumerole",
+	"cannot be assumed",
+	"iam.serviceaccounts.getaccesstoken",
+	"aadsts700016",
+	"throttl",
+	"rate exceeded",
+	"too many requests",
+	"temporarily unavailable",
+	"service unavailable",
+	"timed out",
+	"connection reset",
+	"connection refused",
+}
+
+// propagatingVerificationErrors are fragments of verification errors that
+// credentials created moments ago report until they have propagated, such as
+// a new AWS access key. Wrong credentials report the same errors, so they are
+// only retried for verificationPropagationWindow.
+var propagatingVerificationErrors = []string{
+	"invalidclienttokenid",
+	"security token included in the request is invalid",
+}
+
+// verificationPropagationWindow is how long verification errors caused by
+// propagating credentials are retried.
+var verificationPropagationWindow = time.Minute
+
+// permanentVerificationErrors are fragments of verification errors caused by
+// wrong credentials or missing permissions, which retrying cannot fix.
+var permanentVerificationErrors = []string{
+	"signaturedoesnotmatch",
+	"invalidaccesskeyid",
+	"unrecognizedclient",
+	"expiredtoken",
+	"invalid_grant",
+	"invalid_client",
+	"access denied",
+	"accessdenied",
+	"permission",
+	"forbidden",
+	"unauthorized",
+	"not authorized",
+	"unable to locate credentials",
+	"could not find default credentials",
+	"has expired",
+	"aadsts",
+}
+
+// isTransientVerificationError reports whether a verification error may go
+// away when verification is retried. Errors that are neither known to be
+// transient nor known to be permanent are retried. Errors of propagating
+// credentials are not transient, see isPropagatingVerificationError.
+func isTransientVerificationError(message string) bool {
+	message = strings.ToLower(message)
+	for _, fragment := range transientVerificationErrors {
+		if strings.Contains(message, fragment) {
+			return true
+		}
+	}
+	if isPropagatingVerificationError(message) {
+		return false
+	}
+	for _, fragment := range permanentVerificationErrors {
+		if strings.Contains(message, fragment) {
+			return false
+		}
+	}
+	return true
+}
+
+// isPropagatingVerificationError reports whether a verification error may be
+// caused by credentials that have not propagated yet.
+func isPropagatingVerificationError(message string) bool {
+	message = strings.ToLower(message)
+	for _, fragment := range propagatingVerificationErrors {
+		if strings.Contains(message, fragment) {
+			return true
+		}
+	}
+	return false
+}
+
+// verifyServiceConnector verifies a service connector configuration with the
+// ZenML server. Transient failures are retried with backoff until the
+// timeout expires, failures of propagating credentials for
+// verificationPropagationWindow, other failures fail at once. Errors reported for
+// individual resource types are added as separate diagnostics, as warnings if
+// the connector itself could be verified.
+func (r *ServiceConnectorResource) verifyServiceConnector(
+	ctx context.Context,
+	connectorReq ServiceConnectorRequest,
+	timeout time.Duration,
+	diags *diag.Diagnostics,
+) *ServiceConnectorResources {
+	var result *ServiceConnectorResources
+	start := time.Now()
+	retryErr := retry.RetryContext(ctx, timeout, func() *retry.RetryError {
+		validation, err := r.client.VerifyServiceConnector(ctx, connectorReq)
+		if err != nil {
+			return retry.NonRetryableError(fmt.Errorf("unable to verify service connector configuration, got error: %s", err))
 		}
+		result = validation
+		if validation.Error == nil {
+			return nil
+		}
+
+		err = fmt.Errorf("Error verifying service connector configuration: %s", *validation.Error)
+		propagating := isPropagatingVerificationError(*validation.Error) &&
+			time.Since(start) < verificationPropagationWindow
+		if propagating || isTransientVerificationError(*validation.Error) {
+			tflog.Debug(ctx, "retrying service connector verification", map[string]interface{}{
+				"error": *validation.Error,
+			})
+			return retry.RetryableError(err)
+		}
+		return retry.NonRetryableError(err)
+	})
+	if retryErr != nil {
+		diags.AddError("Verification Error", retryErr.Error())
 	}
+	if result == nil {
+		return nil
+	}
+
+	for _, resources := range result.Resources {
+		if resources.Error == nil {
+			continue
+		}
+		summary := fmt.Sprintf("Verification Error for %s", resources.ResourceType)
+		detail := fmt.Sprintf("The service connector cannot access %s resources: %s", resources.ResourceType, *resources.Error)
+		if retryErr != nil {
+			diags.AddError(summary, detail)
+		} else {
+			diags.AddWarning(summary, detail)
+		}
+	}
+	return result
+}
+
+// verifiedResources converts a verification result to the value of the
+// resources attribute, leaving out resource types that could not be accessed.
+func verifiedResources(ctx context.Context, result *ServiceConnectorResources, diags *diag.Diagnostics) types.Map {
+	resourceType := types.ListType{ElemType: types.StringType}
+	if result == nil {
+		return types.MapNull(resourceType)
+	}
+
+	resources := make(map[string][]string, len(result.Resources))
+	for _, r := range result.Resources {
+		if r.Error != nil {
+			continue
+		}
+		ids := r.ResourceIDs
+		if ids == nil {
+			ids = []string{}
+		}
+		resources[r.ResourceType] = ids
+	}
+	value, mapDiags := types.MapValueFrom(ctx, resourceType, resources)
+	diags.Append(mapDiags...)
+	return value
 }
 
 func (r *ServiceConnectorResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
@@ -436,27 +919,27 @@ func (r *ServiceConnectorResource) Create(ctx context.Context, req resource.Crea
 		return
 	}
 
+	if secrets := writeOnlySecrets(ctx, req.Config, &resp.Diagnostics); secrets != nil {
+		connectorReq.Secrets = secrets
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	verify := true
 	if !data.Verify.IsNull() {
 		verify = data.Verify.ValueBool()
 	}
 
+	var verification *ServiceConnectorResources
 	if verify {
-		retryErr := retry.RetryContext(ctx, createTimeout, func() *retry.RetryError {
-			validation, err := r.client.VerifyServiceConnector(ctx, *connectorReq)
-			if err != nil {
-				return retry.NonRetryableError(fmt.Errorf("unable to verify service connector configuration, got error: %s", err))
-			}
-			if validation.Error != nil {
-				return retry.RetryableError(fmt.Errorf("Error verifying service connector configuration: %s", *validation.Error))
-			}
-			return nil
-		})
-		if retryErr != nil {
-			resp.Diagnostics.AddError("Verification Error", retryErr.Error())
+		verification = r.verifyServiceConnector(ctx, *connectorReq, createTimeout, &resp.Diagnostics)
+		if resp.Diagnostics.HasError() {
 			return
 		}
 	}
+	data.Resources = verifiedResources(ctx, verification, &resp.Diagnostics)
+	data.VerificationError = types.StringNull()
 
 	tflog.Trace(ctx, "creating service connector")
 
@@ -466,20 +949,26 @@ func (r *ServiceConnectorResource) Create(ctx context.Context, req resource.Crea
 		return
 	}
 
-	r.populateServiceConnectorModel(ctx, connector, &data, &resp.Diagnostics, false)
+	r.populateServiceConnectorModel(ctx, connector, &data, &resp.Diagnostics, configurationKeep)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
 	tflog.Trace(ctx, "created a service connector")
 
+	if resp.Private != nil {
+		recordProviderLabels(ctx, resp.Private, r.client, &resp.Diagnostics)
+	}
+
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	setResourceIdentity(ctx, r.client, data.ID.ValueString(), resp.Identity, &resp.Diagnostics)
 }
 
 func (r *ServiceConnectorResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
 	var data ServiceConnectorResourceModel
 
 	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	checkResourceIdentity(ctx, r.client, "service connector", data.ID.ValueString(), req.Identity, &resp.Diagnostics)
 
 	if resp.Diagnostics.HasError() {
 		return
@@ -498,12 +987,49 @@ func (r *ServiceConnectorResource) Read(ctx context.Context, req resource.ReadRe
 		return
 	}
 
-	r.populateServiceConnectorModel(ctx, connector, &data, &resp.Diagnostics, true)
+	refresh := readConfigurationRefresh(ctx, req, resp)
+	r.populateServiceConnectorModel(ctx, connector, &data, &resp.Diagnostics, refresh)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
+	if data.VerifyOnRead.ValueBool() {
+		r.verifyOnRead(ctx, &data, &resp.Diagnostics)
+	} else {
+		data.VerificationError = types.StringNull()
+	}
+
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	setResourceIdentity(ctx, r.client, data.ID.ValueString(), resp.Identity, &resp.Diagnostics)
+}
+
+// verifyOnRead verifies the stored configuration of a service connector
+// during refresh and records the result in state. Failures are warnings, so
+// that the refresh completes and the plan shows the update they cause.
+func (r *ServiceConnectorResource) verifyOnRead(ctx context.Context, data *ServiceConnectorResourceModel, diags *diag.Diagnostics) {
+	result, err := r.client.VerifyExistingServiceConnector(ctx, data.ID.ValueString(), "", "")
+	if err != nil {
+		diags.AddWarning("Client Error",
+			fmt.Sprintf("Unable to verify service connector, got error: %s", err))
+		return
+	}
+
+	data.Resources = verifiedResources(ctx, result, diags)
+	data.VerificationError = types.StringNull()
+	if result.Error != nil {
+		data.VerificationError = types.StringValue(*result.Error)
+		diags.AddWarning(
+			"Service Connector Verification Failed",
+			fmt.Sprintf(
+				"Service connector '%s' could not be verified, its credentials may have expired "+
+					"or been revoked: %s\n\nUpdating the connector with the same credentials would not "+
+					"repair it. Provide new credentials by changing secret_configuration, by setting new "+
+					"secrets_wo values with a new secrets_wo_version, or by refreshing the local "+
+					"credentials read by auto_configure.",
+				data.Name.ValueString(), *result.Error,
+			),
+		)
+	}
 }
 
 func (r *ServiceConnectorResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
@@ -527,31 +1053,61 @@ func (r *ServiceConnectorResource) Update(ctx context.Context, req resource.Upda
 		return
 	}
 
+	var state ServiceConnectorResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+
+	if isAdditiveLabels(data.LabelsMode) {
+		current, err := r.client.GetServiceConnector(ctx, data.ID.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read service connector labels, got error: %s", err))
+			return
+		}
+		if current != nil && current.Metadata != nil {
+			connectorReq.Labels = additiveLabels(current.Metadata.Labels, state.Labels, appliedProviderLabels(ctx, req.Private, &resp.Diagnostics), connectorReq.Labels)
+		}
+	}
+
+	// Write-only secrets are always part of the configuration, so
+	// verification sees them.
+	writeOnly := writeOnlySecrets(ctx, req.Config, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	renewing := false
+	if req.Private != nil {
+		value, diags := req.Private.GetKey(ctx, connectorRenewingPrivateKey)
+		resp.Diagnostics.Append(diags...)
+		renewing = len(value) > 0
+	}
+	updateSecrets := connectorUpdateSecrets(&data, &state, connectorReq.Secrets, writeOnly, renewing)
+	if writeOnly != nil {
+		connectorReq.Secrets = writeOnly
+	}
+
 	verify := true
 	if !data.Verify.IsNull() {
 		verify = data.Verify.ValueBool()
 	}
 
+	var verification *ServiceConnectorResources
 	if verify {
-		retryErr := retry.RetryContext(ctx, updateTimeout, func() *retry.RetryError {
-			validation, err := r.client.VerifyServiceConnector(ctx, *connectorReq)
-			if err != nil {
-				return retry.NonRetryableError(fmt.Errorf("unable to verify service connector configuration, got error: %s", err))
-			}
-			if validation.Error != nil {
-				return retry.RetryableError(fmt.Errorf("Error verifying service connector configuration: %s", *validation.Error))
-			}
-			return nil
-		})
-		if retryErr != nil {
-			resp.Diagnostics.AddError("Verification Error", retryErr.Error())
+		verification = r.verifyServiceConnector(ctx, *connectorReq, updateTimeout, &resp.Diagnostics)
+		if resp.Diagnostics.HasError() {
 			return
 		}
 	}
+	data.Resources = verifiedResources(ctx, verification, &resp.Diagnostics)
+	// An update without verification only repairs a failed verification when
+	// it sends new credentials.
+	data.VerificationError = state.VerificationError
+	if verify || updateSecrets != nil {
+		data.VerificationError = types.StringNull()
+	}
 
 	updateReq := ServiceConnectorUpdate{
 		Name:          connectorReq.Name,
 		Configuration: connectorReq.Configuration,
+		Secrets:       updateSecrets,
 		Labels:        connectorReq.Labels,
 		ResourceTypes: connectorReq.ResourceTypes,
 		ResourceID:    connectorReq.ResourceID,
@@ -566,12 +1122,21 @@ func (r *ServiceConnectorResource) Update(ctx context.Context, req resource.Upda
 		return
 	}
 
-	r.populateServiceConnectorModel(ctx, connector, &data, &resp.Diagnostics, true)
+	r.populateServiceConnectorModel(ctx, connector, &data, &resp.Diagnostics, configurationKeep)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
+	if resp.Private != nil {
+		resp.Diagnostics.Append(resp.Private.SetKey(ctx, connectorRenewingPrivateKey, nil)...)
+	}
+
+	if resp.Private != nil {
+		recordProviderLabels(ctx, resp.Private, r.client, &resp.Diagnostics)
+	}
+
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	setResourceIdentity(ctx, r.client, data.ID.ValueString(), resp.Identity, &resp.Diagnostics)
 }
 
 func (r *ServiceConnectorResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
@@ -583,15 +1148,295 @@ func (r *ServiceConnectorResource) Delete(ctx context.Context, req resource.Dele
 		return
 	}
 
+	if isDeletionProtected(data.DeletionProtection) {
+		addDeletionProtectionError(&resp.Diagnostics, "service connector", data.Name.ValueString(), "delete")
+		return
+	}
+
+	deleteTimeout, tDiags := data.Timeouts.Delete(ctx, time.Minute)
+	resp.Diagnostics.Append(tDiags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	tflog.Trace(ctx, "deleting service connector")
 
-	err := r.client.DeleteServiceConnector(ctx, data.ID.ValueString())
+	detach := data.OnDelete.ValueString() == connectorOnDeleteDetach
+
+	var components []ComponentResponse
+	var err error
+	if detach {
+		components, err = r.client.ListComponentsByConnector(ctx, data.ID.ValueString())
+	} else {
+		components, err = r.waitForDetachment(ctx, data.ID.ValueString(), deleteTimeout)
+	}
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Client Error",
+			fmt.Sprintf("Unable to list components using service connector: %s", err),
+		)
+		return
+	}
+
+	if len(components) > 0 {
+		if !detach {
+			resp.Diagnostics.AddError(
+				"Service Connector In Use",
+				fmt.Sprintf(
+					"Cannot delete service connector '%s' because it is still used "+
+						"after waiting %s by %d stack component(s):\n\n  - %s\n\n"+
+						"Remove the connector from these components or "+
+						"delete them first, or set on_delete = \"%s\" to "+
+						"detach the connector from them automatically.",
+					data.Name.ValueString(),
+					deleteTimeout,
+					len(components),
+					describeComponents(components),
+					connectorOnDeleteDetach,
+				),
+			)
+			return
+		}
+
+		for _, component := range components {
+			tflog.Info(ctx, fmt.Sprintf(
+				"Removing service connector %s from component %s before deletion",
+				data.ID.ValueString(),
+				component.Name,
+			))
+			if err := r.client.RemoveConnectorFromComponent(ctx, component.ID); err != nil {
+				resp.Diagnostics.AddError(
+					"Client Error",
+					fmt.Sprintf(
+						"Unable to remove service connector from component %s: %s",
+						component.Name,
+						err,
+					),
+				)
+				return
+			}
+		}
+	}
+
+	err = r.client.DeleteServiceConnector(ctx, data.ID.ValueString())
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to delete service connector, got error: %s", err))
 		return
 	}
 }
 
+// describeComponents lists stack components by name, type and ID, one per
+// line, for diagnostics.
+func describeComponents(components []ComponentResponse) string {
+	names := make([]string, len(components))
+	for i, c := range components {
+		componentType := ""
+		if c.Body != nil {
+			componentType = c.Body.Type
+		}
+		names[i] = fmt.Sprintf("%s (%s, %s)", c.Name, componentType, c.ID)
+	}
+	return strings.Join(names, "\n  - ")
+}
+
+// waitForDetachment waits until no stack component uses a service connector
+// or the timeout expires, and returns the components that still use it.
+// Components moved to another connector in the same apply are updated
+// before the connector is deleted, but the update may take a moment to be
+// visible.
+func (r *ServiceConnectorResource) waitForDetachment(
+	ctx context.Context,
+	connectorID string,
+	timeout time.Duration,
+) ([]ComponentResponse, error) {
+	var components []ComponentResponse
+	var listErr error
+	_ = retry.RetryContext(ctx, timeout, func() *retry.RetryError {
+		components, listErr = r.client.ListComponentsByConnector(ctx, connectorID)
+		if listErr != nil {
+			return retry.NonRetryableError(listErr)
+		}
+		if len(components) > 0 {
+			tflog.Debug(ctx, "waiting for stack components to stop using service connector", map[string]interface{}{
+				"components": len(components),
+			})
+			return retry.RetryableError(fmt.Errorf("service connector is used by %d stack component(s)", len(components)))
+		}
+		return nil
+	})
+	return components, listErr
+}
+
+func (r *ServiceConnectorResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	replace := false
+	if !req.State.Raw.IsNull() && !req.Plan.Raw.IsNull() {
+		replace = planChangesAttributes(ctx, req, &resp.Diagnostics, path.Root("type"), path.Root("auth_method"), path.Root("resource_type"), path.Root("resource_types"), path.Root("resource_id"))
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	checkDeletionProtection(ctx, req, resp, "service connector", "name", replace)
+	if resp.Diagnostics.HasError() || req.Plan.Raw.IsNull() {
+		return
+	}
+
+	if replace {
+		r.planReplacement(ctx, req, resp)
+	}
+	r.planAutoConfiguration(ctx, req, resp)
+	if planLabelsAll(ctx, r.client, req, resp) {
+		r.planRefreshedAttributes(ctx, resp)
+	}
+	if !req.State.Raw.IsNull() {
+		r.planCredentialRenewal(ctx, req, resp)
+	}
+}
+
+// planReplacement checks the stack components that use a connector that is
+// replaced. Terraform deletes the old connector before it creates the
+// replacement, unless the connector sets create_before_destroy, which the
+// provider cannot see. The components that reference the connector are only
+// moved to the replacement after it was created, so with on_delete = "fail"
+// the delete would wait for them in vain. The plan fails instead, naming the
+// components. With on_delete = "detach", the plan warns about them.
+func (r *ServiceConnectorResource) planReplacement(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	var id, name, onDelete types.String
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("id"), &id)...)
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("name"), &name)...)
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("on_delete"), &onDelete)...)
+	if resp.Diagnostics.HasError() || id.IsNull() || onDelete.IsUnknown() {
+		return
+	}
+
+	components, err := r.client.ListComponentsByConnector(ctx, id.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error",
+			fmt.Sprintf("Unable to list components using service connector, got error: %s", err))
+		return
+	}
+	if len(components) == 0 {
+		return
+	}
+
+	if onDelete.ValueString() != connectorOnDeleteDetach {
+		resp.Diagnostics.AddError(
+			"Service Connector Replaced While In Use",
+			fmt.Sprintf(
+				"Service connector '%s' must be replaced, but %d stack component(s) use it:\n\n  - %s\n\n"+
+					"Terraform deletes the old connector before it creates the replacement, so these "+
+					"components cannot be moved to the replacement before the old connector is deleted. "+
+					"Set on_delete = \"%s\" to remove the old connector from them before it is deleted; "+
+					"components that reference the connector in this configuration are then connected "+
+					"to the replacement in the same apply. Otherwise remove the connector from these "+
+					"components first.",
+				name.ValueString(),
+				len(components),
+				describeComponents(components),
+				connectorOnDeleteDetach,
+			),
+		)
+		return
+	}
+
+	resp.Diagnostics.AddWarning(
+		"Service Connector Replaced While In Use",
+		fmt.Sprintf(
+			"Service connector '%s' is replaced while %d stack component(s) use it:\n\n  - %s\n\n"+
+				"The old connector is removed from these components before it is deleted. "+
+				"Components that reference the connector in this configuration are connected "+
+				"to the replacement in the same apply; others are left without a connector.",
+			name.ValueString(),
+			len(components),
+			describeComponents(components),
+		),
+	)
+}
+
+// planCredentialRenewal plans an update of a connector whose auto-configured
+// credentials changed or whose write-only credentials are renewed before they
+// expire, and warns about credentials that are about to expire. The update
+// sends the configured credentials and verifies them again. A verification
+// that failed during refresh does not plan an update by itself, because
+// sending the same credentials again would not repair it. Computed
+// attributes that the update refreshes are marked unknown, because the
+// framework only does so for changes that exist before ModifyPlan.
+func (r *ServiceConnectorResource) planCredentialRenewal(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	var plan, state ServiceConnectorResourceModel
+	var planFingerprint, stateFingerprint types.String
+	var secretsWO types.Map
+	fingerprint := path.Root("auto_configure").AtName("fingerprint")
+	resp.Diagnostics.Append(resp.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	resp.Diagnostics.Append(resp.Plan.GetAttribute(ctx, fingerprint, &planFingerprint)...)
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, fingerprint, &stateFingerprint)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("secrets_wo"), &secretsWO)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	autoConfigured := !planFingerprint.Equal(stateFingerprint)
+	renewed := autoConfigured ||
+		!plan.SecretConfiguration.Equal(state.SecretConfiguration) ||
+		!plan.SecretsWOVersion.Equal(state.SecretsWOVersion)
+	renew := checkCredentialExpiry(&plan, &state, renewed, !secretsWO.IsNull(), &resp.Diagnostics)
+	if renew && resp.Private != nil {
+		resp.Diagnostics.Append(resp.Private.SetKey(ctx, connectorRenewingPrivateKey, []byte("true"))...)
+	}
+
+	if !autoConfigured && !renew {
+		return
+	}
+	r.planRefreshedAttributes(ctx, resp)
+}
+
+// planRefreshedAttributes marks the computed attributes that an update of the
+// connector refreshes unknown, and clears the error of the last verification.
+func (r *ServiceConnectorResource) planRefreshedAttributes(ctx context.Context, resp *resource.ModifyPlanResponse) {
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("verification_error"), types.StringNull())...)
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("resources"), types.MapUnknown(types.ListType{ElemType: types.StringType}))...)
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("effective_configuration"), types.MapUnknown(types.StringType))...)
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("expires_at"), types.StringUnknown())...)
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("updated"), types.StringUnknown())...)
+}
+
+// planAutoConfiguration reads the local credentials of auto_configure at plan
+// time, so that invalid or missing credentials fail the plan and changed
+// credentials show up as a change of the fingerprint.
+func (r *ServiceConnectorResource) planAutoConfiguration(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	var plan ServiceConnectorResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() || plan.AutoConfigure.IsNull() || plan.AutoConfigure.IsUnknown() ||
+		plan.Type.IsUnknown() || plan.AuthMethod.IsUnknown() || plan.Name.IsUnknown() {
+		return
+	}
+
+	var block ServiceConnectorAutoConfigureModel
+	resp.Diagnostics.Append(plan.AutoConfigure.As(ctx, &block, basetypes.ObjectAsOptions{})...)
+	if resp.Diagnostics.HasError() || block.AWSProfile.IsUnknown() || block.GCPCredentialsFile.IsUnknown() ||
+		block.KubernetesContext.IsUnknown() || block.DockerRegistry.IsUnknown() {
+		return
+	}
+
+	auto, err := autoConfigure(plan.Type.ValueString(), plan.AuthMethod.ValueString(), block.options())
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("auto_configure"), "Auto-configuration Error",
+			fmt.Sprintf("Unable to auto-configure service connector, got error: %s", err))
+		return
+	}
+
+	fingerprint := auto.Fingerprint(plan.Type.ValueString(), plan.AuthMethod.ValueString(), plan.Name.ValueString())
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("auto_configure").AtName("fingerprint"), fingerprint)...)
+}
+
 func (r *ServiceConnectorResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+	if !importStateFromIdentity(ctx, r.client, "service connector", req, resp) {
+		importStateByNameOrID(ctx, req, resp, "service connector",
+			map[string]string{"name": req.ID},
+			r.client.ListServiceConnectors,
+			func(c ServiceConnectorResponse) string { return c.ID },
+		)
+	}
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("deletion_protection"), false)...)
+	markImported(ctx, resp)
 }
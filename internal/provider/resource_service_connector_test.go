@@ -1,10 +1,22 @@
 package provider
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	fwresource "github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/tfversion"
 )
 
 func TestAccServiceConnector_basic(t *testing.T) {
@@ -29,12 +41,368 @@ func TestAccServiceConnector_basic(t *testing.T) {
 				ResourceName:            "zenml_service_connector.test",
 				ImportState:             true,
 				ImportStateVerify:       true,
-				ImportStateVerifyIgnore: []string{"secrets"},
+				ImportStateVerifyIgnore: []string{"secret_configuration", "resources"},
 			},
 		},
 	})
 }
 
+func TestAccServiceConnector_writeOnlySecrets(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_11_0),
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: testAccServiceConnectorConfig_writeOnly(1),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckNoResourceAttr("zenml_service_connector.test", "secrets_wo"),
+					resource.TestCheckResourceAttr("zenml_service_connector.test", "secrets_wo_version", "1"),
+				),
+			},
+			{
+				Config: testAccServiceConnectorConfig_writeOnly(2),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckNoResourceAttr("zenml_service_connector.test", "secrets_wo"),
+					resource.TestCheckResourceAttr("zenml_service_connector.test", "secrets_wo_version", "2"),
+				),
+			},
+		},
+	})
+}
+
+/*
+TestAccServiceConnector_replaceInUse replaces a connector that a stack
+component uses, without create_before_destroy:
+1. Create a connector with on_delete = "detach" and a component using it.
+2. Change the resource ID of the connector, which replaces it. The old
+   connector is detached from the component before it is deleted, and the
+   component is updated in place to use the replacement.
+*/
+
+func TestAccServiceConnector_replaceInUse(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccServiceConnectorConfig_inUse("test-artifacts-1"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair(
+						"zenml_stack_component.test", "connector_id", "zenml_service_connector.test", "id"),
+				),
+			},
+			{
+				Config: testAccServiceConnectorConfig_inUse("test-artifacts-2"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(
+						"zenml_service_connector.test", "resource_id", "test-artifacts-2"),
+					resource.TestCheckResourceAttrPair(
+						"zenml_stack_component.test", "connector_id", "zenml_service_connector.test", "id"),
+				),
+			},
+		},
+	})
+}
+
+func TestServiceConnectorPopulate_ResourceTypes(t *testing.T) {
+	ctx := context.Background()
+	r := &ServiceConnectorResource{}
+	connector := &ServiceConnectorResponse{
+		ID:   "connector-1",
+		Name: "aws",
+		Body: &ServiceConnectorResponseBody{
+			ConnectorType: []byte(`"aws"`),
+			AuthMethod:    "secret-key",
+			ResourceTypes: []string{"s3-bucket", "kubernetes-cluster", "docker-registry"},
+		},
+	}
+
+	var diags diag.Diagnostics
+	adopted := ServiceConnectorResourceModel{
+		ResourceType:  types.StringNull(),
+		ResourceTypes: types.SetNull(types.StringType),
+		Configuration: types.MapNull(types.StringType),
+	}
+	r.populateServiceConnectorModel(ctx, connector, &adopted, &diags, configurationAdopt)
+	if diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+	if !adopted.ResourceType.IsNull() || len(adopted.ResourceTypes.Elements()) != 3 {
+		t.Errorf("expected imported multi-type connectors to use resource_types, got %s and %s",
+			adopted.ResourceType, adopted.ResourceTypes)
+	}
+
+	connector.Body.ResourceTypes = []string{"s3-bucket"}
+	tracked := ServiceConnectorResourceModel{
+		ResourceType:  types.StringNull(),
+		ResourceTypes: adopted.ResourceTypes,
+		Configuration: types.MapNull(types.StringType),
+	}
+	r.populateServiceConnectorModel(ctx, connector, &tracked, &diags, configurationTrack)
+	if diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+	if !tracked.ResourceType.IsNull() || len(tracked.ResourceTypes.Elements()) != 1 {
+		t.Errorf("expected resource_types to track the server, got %s and %s",
+			tracked.ResourceType, tracked.ResourceTypes)
+	}
+}
+
+func TestIsTransientVerificationError(t *testing.T) {
+	cases := map[string]bool{
+		"An error occurred (InvalidClientTokenId): The security token included in the request is invalid": false,
+		"An error occurred (SignatureDoesNotMatch) when calling the GetCallerIdentity operation":          false,
+		"An error occurred (InvalidAccessKeyId) when calling the ListBuckets operation":                   false,
+		"User: arn:aws:iam::123456789012:user/ci is not authorized to perform: sts:AssumeRole":            true,
+		"403 Permission 'storage.buckets.get' denied on resource":                                         false,
+		"AADSTS7000215: Invalid client secret provided.":                                                  false,
+		"AADSTS700016: Application with identifier 'abc' was not found in the directory":                  true,
+		"An error occurred (Throttling) when calling the AssumeRole operation: Rate exceeded":             true,
+		"invalid configuration: region is required":                                                       true,
+		"something unexpected happened":                                                                   true,
+	}
+	for message, want := range cases {
+		if got := isTransientVerificationError(message); got != want {
+			t.Errorf("expected %q to be transient: %t, got %t", message, want, got)
+		}
+	}
+
+	if !isPropagatingVerificationError("An error occurred (InvalidClientTokenId): The security token included in the request is invalid") {
+		t.Errorf("expected an invalid client token to be retried while new credentials propagate")
+	}
+}
+
+func TestServiceConnectorVerify_RetriesPropagatingCredentials(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		calls++
+		result := ServiceConnectorResources{}
+		if calls == 1 {
+			// A freshly created access key is rejected until it has
+			// propagated.
+			message := "An error occurred (InvalidClientTokenId): The security token included in the request is invalid"
+			result.Error = &message
+		}
+		_ = json.NewEncoder(w).Encode(result)
+	}))
+	defer server.Close()
+
+	r := &ServiceConnectorResource{client: NewClient(server.URL, "", "test-token")}
+	var diags diag.Diagnostics
+	r.verifyServiceConnector(context.Background(), ServiceConnectorRequest{}, time.Minute, &diags)
+	if diags.HasError() || calls != 2 {
+		t.Errorf("expected verification to be retried once, got %d calls and %v", calls, diags)
+	}
+
+	// Credentials that still fail after the propagation window are wrong.
+	window := verificationPropagationWindow
+	verificationPropagationWindow = 0
+	defer func() { verificationPropagationWindow = window }()
+	calls = 0
+	r.verifyServiceConnector(context.Background(), ServiceConnectorRequest{}, time.Minute, &diags)
+	if !diags.HasError() || calls != 1 {
+		t.Errorf("expected verification to fail after the propagation window, got %d calls and %v", calls, diags)
+	}
+}
+
+func TestServiceConnectorVerify_FailsFastOnPermanentErrors(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		calls++
+		message := "An error occurred (SignatureDoesNotMatch) when calling the GetCallerIdentity operation"
+		_ = json.NewEncoder(w).Encode(ServiceConnectorResources{
+			Error: &message,
+			Resources: []ServiceConnectorTypedResources{
+				{ResourceType: "s3-bucket", Error: &message},
+				{ResourceType: "docker-registry", ResourceIDs: []string{"123456789012.dkr.ecr.us-east-1.amazonaws.com"}},
+			},
+		})
+	}))
+	defer server.Close()
+
+	r := &ServiceConnectorResource{client: NewClient(server.URL, "", "test-token")}
+	var diags diag.Diagnostics
+	start := time.Now()
+	r.verifyServiceConnector(context.Background(), ServiceConnectorRequest{}, time.Minute, &diags)
+
+	if calls != 1 || time.Since(start) > 10*time.Second {
+		t.Errorf("expected verification to fail without retrying, got %d calls", calls)
+	}
+	if diags.ErrorsCount() != 2 || !strings.Contains(diags.Errors()[1].Summary(), "s3-bucket") {
+		t.Errorf("expected the connector and s3-bucket errors, got %v", diags)
+	}
+}
+
+func TestServiceConnectorVerifyOnRead(t *testing.T) {
+	ctx := context.Background()
+	message := "The security token included in the request has expired"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodPut || req.URL.Path != "/api/v1/service_connectors/connector-1/verify" {
+			http.NotFound(w, req)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(ServiceConnectorResources{
+			Error: &message,
+			Resources: []ServiceConnectorTypedResources{
+				{ResourceType: "s3-bucket", Error: &message},
+				{ResourceType: "docker-registry", ResourceIDs: []string{"123456789012.dkr.ecr.us-east-1.amazonaws.com"}},
+			},
+		})
+	}))
+	defer server.Close()
+
+	r := &ServiceConnectorResource{client: NewClient(server.URL, "", "test-token")}
+	data := ServiceConnectorResourceModel{ID: types.StringValue("connector-1"), Name: types.StringValue("aws")}
+	var diags diag.Diagnostics
+	r.verifyOnRead(ctx, &data, &diags)
+
+	if diags.HasError() || diags.WarningsCount() != 1 {
+		t.Errorf("expected a single warning, got %v", diags)
+	}
+	if data.VerificationError.ValueString() != message {
+		t.Errorf("expected the verification error in state, got %s", data.VerificationError)
+	}
+	resources := data.Resources.Elements()
+	if _, ok := resources["s3-bucket"]; ok || len(resources) != 1 {
+		t.Errorf("expected only the accessible resource types, got %s", data.Resources)
+	}
+}
+
+func TestServiceConnectorPlan_KeepsFailedVerification(t *testing.T) {
+	ctx := context.Background()
+	schemaResp := &fwresource.SchemaResponse{}
+	(&ServiceConnectorResource{}).Schema(ctx, fwresource.SchemaRequest{}, schemaResp)
+
+	state := tfsdk.State{Schema: schemaResp.Schema, Raw: nullAttributesValue(schemaResp.Schema.Type().TerraformType(ctx))}
+	diags := state.SetAttribute(ctx, path.Root("name"), "aws")
+	diags.Append(state.SetAttribute(ctx, path.Root("verification_error"), "The security token included in the request has expired")...)
+	if diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+	plan := tfsdk.Plan{Schema: state.Schema, Raw: state.Raw.Copy()}
+
+	// Without new credentials, an update would not repair the connector.
+	resp := &fwresource.ModifyPlanResponse{Plan: plan}
+	(&ServiceConnectorResource{}).planCredentialRenewal(ctx, fwresource.ModifyPlanRequest{
+		Config: tfsdk.Config{Schema: plan.Schema, Raw: plan.Raw},
+		State:  state,
+		Plan:   plan,
+	}, resp)
+	if resp.Diagnostics.HasError() || !resp.Plan.Raw.Equal(state.Raw) {
+		t.Errorf("expected no update without new credentials, got %v", resp.Diagnostics)
+	}
+}
+
+func TestServiceConnectorWaitForDetachment(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		calls++
+		items := []ComponentResponse{}
+		if calls == 1 {
+			// The first listing still sees a component that is being moved
+			// to another connector.
+			items = append(items, ComponentResponse{ID: "component-1", Name: "orchestrator"})
+		}
+		_ = json.NewEncoder(w).Encode(Page[ComponentResponse]{Index: 1, TotalPages: 1, Total: len(items), Items: items})
+	}))
+	defer server.Close()
+
+	r := &ServiceConnectorResource{client: NewClient(server.URL, "", "test-token")}
+	components, err := r.waitForDetachment(context.Background(), "connector-1", 10*time.Second)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(components) != 0 || calls != 2 {
+		t.Errorf("expected to wait for the component to be detached, got %v after %d calls", components, calls)
+	}
+}
+
+func TestConnectorUpdateSecrets(t *testing.T) {
+	ctx := context.Background()
+	configured, _ := types.MapValueFrom(ctx, types.StringType, map[string]string{"aws_secret_access_key": "old"})
+	removed := types.MapNull(types.StringType)
+	version := func(v int64) types.Int64 { return types.Int64Value(v) }
+
+	encode := func(secrets *map[string]string) string {
+		body, err := json.Marshal(ServiceConnectorUpdate{Secrets: secrets})
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		return string(body)
+	}
+
+	// Removing secret_configuration clears the secrets on the server.
+	cleared := connectorUpdateSecrets(
+		&ServiceConnectorResourceModel{SecretConfiguration: removed},
+		&ServiceConnectorResourceModel{SecretConfiguration: configured},
+		nil, nil, false,
+	)
+	if body := encode(cleared); !strings.Contains(body, `"secrets":{}`) {
+		t.Errorf("expected an empty secrets map to be sent, got %s", body)
+	}
+
+	// Connectors without secret_configuration keep their stored secrets.
+	kept := connectorUpdateSecrets(
+		&ServiceConnectorResourceModel{SecretConfiguration: removed},
+		&ServiceConnectorResourceModel{SecretConfiguration: removed},
+		nil, nil, false,
+	)
+	if body := encode(kept); strings.Contains(body, "secrets") {
+		t.Errorf("expected no secrets to be sent, got %s", body)
+	}
+
+	writeOnly := map[string]string{"aws_secret_access_key": "new"}
+	unchanged := connectorUpdateSecrets(
+		&ServiceConnectorResourceModel{SecretConfiguration: removed, SecretsWOVersion: version(1)},
+		&ServiceConnectorResourceModel{SecretConfiguration: removed, SecretsWOVersion: version(1)},
+		nil, writeOnly, false,
+	)
+	if unchanged != nil {
+		t.Errorf("expected write-only secrets to be sent only on version changes, got %v", *unchanged)
+	}
+	changed := connectorUpdateSecrets(
+		&ServiceConnectorResourceModel{SecretConfiguration: removed, SecretsWOVersion: version(2)},
+		&ServiceConnectorResourceModel{SecretConfiguration: removed, SecretsWOVersion: version(1)},
+		nil, writeOnly, false,
+	)
+	if changed == nil || (*changed)["aws_secret_access_key"] != "new" {
+		t.Errorf("expected the new write-only secrets to be sent, got %v", changed)
+	}
+	renewing := connectorUpdateSecrets(
+		&ServiceConnectorResourceModel{SecretConfiguration: removed, SecretsWOVersion: version(1)},
+		&ServiceConnectorResourceModel{SecretConfiguration: removed, SecretsWOVersion: version(1)},
+		nil, writeOnly, true,
+	)
+	if renewing == nil || (*renewing)["aws_secret_access_key"] != "new" {
+		t.Errorf("expected the write-only secrets to be sent when renewing, got %v", renewing)
+	}
+}
+
+func testAccServiceConnectorConfig_writeOnly(version int) string {
+	return fmt.Sprintf(`
+%s
+
+resource "zenml_service_connector" "test" {
+  name        = "test-connector-wo"
+  type        = "aws"
+  auth_method = "secret-key"
+
+  configuration = {
+    region = "us-east-1"
+  }
+
+  secrets_wo = {
+    aws_access_key_id     = "test-key-%[2]d"
+    aws_secret_access_key = "test-secret-%[2]d"
+  }
+  secrets_wo_version = %[2]d
+}
+`, testAccProviderConfig(), version)
+}
+
 func testAccServiceConnectorConfig_basic() string {
 	return fmt.Sprintf(`
 %s
@@ -48,7 +416,7 @@ resource "zenml_service_connector" "test" {
     region = "us-east-1"
   }
   
-  secrets = {
+  secret_configuration = {
     aws_access_key_id     = "test-key"
     aws_secret_access_key = "test-secret"
   }
@@ -59,3 +427,119 @@ resource "zenml_service_connector" "test" {
 }
 `, testAccProviderConfig())
 }
+
+func TestServiceConnectorConfigValidator_RejectsSecretKeys(t *testing.T) {
+	ctx := context.Background()
+	schemaResp := &fwresource.SchemaResponse{}
+	(&ServiceConnectorResource{}).Schema(ctx, fwresource.SchemaRequest{}, schemaResp)
+
+	validate := func(configuration map[string]string) diag.Diagnostics {
+		plan := tfsdk.Plan{Schema: schemaResp.Schema, Raw: nullAttributesValue(schemaResp.Schema.Type().TerraformType(ctx))}
+		diags := plan.SetAttribute(ctx, path.Root("type"), "aws")
+		diags.Append(plan.SetAttribute(ctx, path.Root("auth_method"), "secret-key")...)
+		diags.Append(plan.SetAttribute(ctx, path.Root("configuration"), configuration)...)
+		if diags.HasError() {
+			t.Fatalf("unexpected diagnostics: %v", diags)
+		}
+		config := tfsdk.Config{Schema: plan.Schema, Raw: plan.Raw}
+		resp := &fwresource.ValidateConfigResponse{}
+		serviceConnectorConfigValidator{}.ValidateResource(ctx, fwresource.ValidateConfigRequest{Config: config}, resp)
+		return resp.Diagnostics
+	}
+
+	diags := validate(map[string]string{"region": "us-east-1", "aws_secret_access_key": "secret"})
+	if !diags.HasError() || !strings.Contains(diags.Errors()[0].Detail(), "[aws_secret_access_key]") {
+		t.Errorf("expected an error for a secret key in configuration, got %v", diags)
+	}
+
+	if diags := validate(map[string]string{"region": "us-east-1"}); diags.HasError() {
+		t.Errorf("unexpected diagnostics: %v", diags)
+	}
+}
+
+func TestServiceConnectorPlanReplacement(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		items := []ComponentResponse{}
+		if req.URL.Query().Get("connector_id") == "connector-1" {
+			items = append(items, ComponentResponse{
+				ID:   "component-1",
+				Name: "artifacts",
+				Body: &ComponentResponseBody{Type: "artifact_store"},
+			})
+		}
+		_ = json.NewEncoder(w).Encode(Page[ComponentResponse]{Index: 1, TotalPages: 1, Total: len(items), Items: items})
+	}))
+	defer server.Close()
+
+	r := &ServiceConnectorResource{client: NewClient(server.URL, "", "test-token")}
+	ctx := context.Background()
+	schemaResp := &fwresource.SchemaResponse{}
+	r.Schema(ctx, fwresource.SchemaRequest{}, schemaResp)
+
+	plan := func(id string, onDelete types.String) *fwresource.ModifyPlanResponse {
+		state := tfsdk.State{Schema: schemaResp.Schema, Raw: nullAttributesValue(schemaResp.Schema.Type().TerraformType(ctx))}
+		diags := state.SetAttribute(ctx, path.Root("id"), id)
+		diags.Append(state.SetAttribute(ctx, path.Root("name"), "aws")...)
+		diags.Append(state.SetAttribute(ctx, path.Root("on_delete"), onDelete)...)
+		if diags.HasError() {
+			t.Fatalf("unexpected diagnostics: %v", diags)
+		}
+		resp := &fwresource.ModifyPlanResponse{}
+		r.planReplacement(ctx, fwresource.ModifyPlanRequest{State: state, Plan: tfsdk.Plan{Schema: state.Schema, Raw: state.Raw}}, resp)
+		return resp
+	}
+
+	// The delete of the old connector would wait for the component forever.
+	resp := plan("connector-1", types.StringNull())
+	if !resp.Diagnostics.HasError() ||
+		!strings.Contains(resp.Diagnostics.Errors()[0].Detail(), "artifacts (artifact_store, component-1)") {
+		t.Errorf("expected an error naming the component, got %v", resp.Diagnostics)
+	}
+
+	resp = plan("connector-1", types.StringValue(connectorOnDeleteDetach))
+	warnings := resp.Diagnostics.Warnings()
+	if resp.Diagnostics.HasError() || len(warnings) != 1 ||
+		!strings.Contains(warnings[0].Detail(), "artifacts (artifact_store, component-1)") {
+		t.Errorf("expected a warning naming the component, got %v", resp.Diagnostics)
+	}
+
+	if resp = plan("connector-2", types.StringNull()); len(resp.Diagnostics) != 0 {
+		t.Errorf("expected no diagnostics for an unused connector, got %v", resp.Diagnostics)
+	}
+}
+
+func testAccServiceConnectorConfig_inUse(bucket string) string {
+	return fmt.Sprintf(`
+%s
+
+resource "zenml_service_connector" "test" {
+  name          = "test-connector-in-use"
+  type          = "aws"
+  auth_method   = "secret-key"
+  resource_type = "s3-bucket"
+  resource_id   = %q
+  on_delete     = "detach"
+
+  configuration = {
+    region = "us-east-1"
+  }
+
+  secret_configuration = {
+    aws_access_key_id     = "test-key"
+    aws_secret_access_key = "test-secret"
+  }
+}
+
+resource "zenml_stack_component" "test" {
+  name   = "test-store-in-use"
+  type   = "artifact_store"
+  flavor = "s3"
+
+  configuration = {
+    path = "s3://%[2]s"
+  }
+
+  connector_id = zenml_service_connector.test.id
+}
+`, testAccProviderConfig(), bucket)
+}
@@ -3,7 +3,9 @@ package provider
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/hashicorp/terraform-plugin-framework/attr"
@@ -19,7 +21,10 @@ import (
 
 var _ resource.Resource = &StackResource{}
 var _ resource.ResourceWithImportState = &StackResource{}
+var _ resource.ResourceWithIdentity = &StackResource{}
+var _ resource.ResourceWithModifyPlan = &StackResource{}
 var _ resource.ResourceWithConfigValidators = &StackResource{}
+var _ resource.ResourceWithUpgradeState = &StackResource{}
 
 // requiresReplaceIfRequiredComponentChanges is a plan modifier that triggers
 // resource replacement when required component types (orchestrator,
@@ -86,19 +91,30 @@ type StackResource struct {
 }
 
 type StackResourceModel struct {
-	ID         types.String `tfsdk:"id"`
-	Name       types.String `tfsdk:"name"`
-	Components types.Map    `tfsdk:"components"`
-	Labels     types.Map    `tfsdk:"labels"`
+	ID                 types.String `tfsdk:"id"`
+	Name               types.String `tfsdk:"name"`
+	Components         types.Map    `tfsdk:"components"`
+	Labels             types.Map    `tfsdk:"labels"`
+	LabelsAll          types.Map    `tfsdk:"labels_all"`
+	LabelsMode         types.String `tfsdk:"labels_mode"`
+	Environment        types.Map    `tfsdk:"environment"`
+	Secrets            types.List   `tfsdk:"secrets"`
+	AdoptExisting      types.Bool   `tfsdk:"adopt_existing"`
+	DeletionProtection types.Bool   `tfsdk:"deletion_protection"`
 }
 
 func (r *StackResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
 	resp.TypeName = req.ProviderTypeName + "_stack"
 }
 
+func (r *StackResource) IdentitySchema(ctx context.Context, req resource.IdentitySchemaRequest, resp *resource.IdentitySchemaResponse) {
+	resp.IdentitySchema = resourceIdentitySchema("stack")
+}
+
 func (r *StackResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
 		MarkdownDescription: "Stack resource",
+		Version:             1,
 
 		Attributes: map[string]schema.Attribute{
 			"id": schema.StringAttribute{
@@ -127,6 +143,24 @@ func (r *StackResource) Schema(ctx context.Context, req resource.SchemaRequest,
 				ElementType:         types.StringType,
 				Optional:            true,
 			},
+			"labels_all":  labelsAllAttribute(),
+			"labels_mode": labelsModeAttribute(),
+			"environment": schema.MapAttribute{
+				MarkdownDescription: "Environment variables that are set for all " +
+					"pipeline steps running on the stack. Use `secrets` for " +
+					"sensitive values.",
+				ElementType: types.StringType,
+				Optional:    true,
+			},
+			"secrets": schema.ListAttribute{
+				MarkdownDescription: "Names or IDs of secrets attached to the " +
+					"stack. Their values are made available to all pipeline " +
+					"steps running on the stack.",
+				ElementType: types.StringType,
+				Optional:    true,
+			},
+			"adopt_existing":      adoptExistingAttribute(),
+			"deletion_protection": deletionProtectionAttribute(),
 		},
 	}
 }
@@ -195,6 +229,14 @@ func (v stackConfigValidator) ValidateResource(ctx context.Context, req resource
 	}
 }
 
+func (r *StackResource) UpgradeState(ctx context.Context) map[int64]resource.StateUpgrader {
+	return map[int64]resource.StateUpgrader{
+		// Version 0 states predate deletion_protection, environment and
+		// secrets.
+		0: rawStateUpgrader(setStateDefault("deletion_protection", false)),
+	}
+}
+
 func (r *StackResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
 	// Prevent panic if the provider has not been configured.
 	if req.ProviderData == nil {
@@ -233,14 +275,94 @@ func (r *StackResource) populateStackModel(
 			data.Components = *componentValue
 		}
 
-		labelValue := flattenStringMapToTFMap(stack.Metadata.Labels, data.Labels, diags)
+		labelValue := flattenLabelsToTFMap(r.client, stack.Metadata.Labels, data.Labels, isAdditiveLabels(data.LabelsMode), diags)
 		if diags.HasError() {
 			return
 		}
 		if labelValue != nil {
 			data.Labels = *labelValue
 		}
+		data.LabelsAll = labelsAllValue(stack.Metadata.Labels, diags)
+
+		environmentValue := flattenStringMapToTFMap(stack.Metadata.Environment, data.Environment, diags)
+		if diags.HasError() {
+			return
+		}
+		if environmentValue != nil {
+			data.Environment = *environmentValue
+		}
+
+		configured := expandStringListFromTF(ctx, data.Secrets, diags)
+		if diags.HasError() {
+			return
+		}
+		resolved, err := r.resolveSecretIDs(ctx, configured)
+		if err != nil {
+			diags.AddError("Client Error", fmt.Sprintf("Unable to resolve stack secrets, got error: %s", err))
+			return
+		}
+		secretsValue := flattenStackSecretsToTFList(stack.Metadata.Secrets, data.Secrets, resolved, diags)
+		if diags.HasError() {
+			return
+		}
+		if secretsValue != nil {
+			data.Secrets = *secretsValue
+		}
+	}
+}
+
+// resolveSecretIDs maps each of the given secret names or IDs to the UUID of
+// the secret it refers to. Secrets that cannot be found are left out.
+func (r *StackResource) resolveSecretIDs(
+	ctx context.Context,
+	namesOrIDs []string,
+) (map[string]string, error) {
+	resolved := make(map[string]string, len(namesOrIDs))
+	for _, nameOrID := range namesOrIDs {
+		id, err := r.client.ResolveSecretID(ctx, nameOrID)
+		if err != nil {
+			return nil, err
+		}
+		if id != "" {
+			resolved[nameOrID] = id
+		}
+	}
+	return resolved, nil
+}
+
+// expandStackSecretIDs resolves the configured stack secrets to their UUIDs
+// and reports the ones that do not exist.
+func (r *StackResource) expandStackSecretIDs(
+	ctx context.Context,
+	secrets types.List,
+	diags *diag.Diagnostics,
+) []string {
+	configured := expandStringListFromTF(ctx, secrets, diags)
+	if diags.HasError() {
+		return nil
+	}
+
+	resolved, err := r.resolveSecretIDs(ctx, configured)
+	if err != nil {
+		diags.AddError("Client Error", fmt.Sprintf("Unable to resolve stack secrets, got error: %s", err))
+		return nil
+	}
+
+	ids := make([]string, 0, len(configured))
+	for _, nameOrID := range configured {
+		id, ok := resolved[nameOrID]
+		if !ok {
+			diags.AddAttributeError(
+				path.Root("secrets"),
+				"Secret Not Found",
+				fmt.Sprintf("No secret found with name or ID %q.", nameOrID),
+			)
+			continue
+		}
+		ids = append(ids, id)
 	}
+
+	return ids
 }
 
 func expandStackComponentsFromTF(
@@ -300,6 +422,32 @@ func expandStringMapFromTF(
 	return result
 }
 
+func expandStringListFromTF(
+	ctx context.Context,
+	values types.List,
+	diags *diag.Diagnostics,
+) []string {
+	result := make([]string, 0)
+	if values.IsNull() || values.IsUnknown() {
+		return result
+	}
+
+	elements := make([]types.String, 0, len(values.Elements()))
+	diags.Append(values.ElementsAs(ctx, &elements, false)...)
+	if diags.HasError() {
+		return nil
+	}
+
+	for _, v := range elements {
+		if v.IsNull() || v.IsUnknown() {
+			continue
+		}
+		result = append(result, v.ValueString())
+	}
+
+	return result
+}
+
 func flattenStringMapToTFMap(
 	values map[string]string,
 	existing types.Map,
@@ -327,6 +475,65 @@ func flattenStringMapToTFMap(
 	return &tfMap
 }
 
+// flattenStackSecretsToTFList reconciles the secret IDs attached to a stack on
+// the server with the names or IDs in the existing list. Entries that still
+// refer to an attached secret are kept as written, so that referencing a
+// secret by name does not produce a diff. Secrets attached outside of
+// Terraform are appended by ID so they show up as drift.
+func flattenStackSecretsToTFList(
+	apiSecrets []string,
+	existing types.List,
+	resolved map[string]string,
+	diags *diag.Diagnostics,
+) *types.List {
+	if len(apiSecrets) == 0 {
+		if !existing.IsNull() && len(existing.Elements()) > 0 {
+			nullList := types.ListNull(types.StringType)
+			return &nullList
+		}
+		return nil
+	}
+
+	attached := make(map[string]bool, len(apiSecrets))
+	for _, id := range apiSecrets {
+		attached[id] = true
+	}
+
+	matched := make(map[string]bool, len(apiSecrets))
+	values := make([]attr.Value, 0, len(apiSecrets))
+
+	if !existing.IsNull() && !existing.IsUnknown() {
+		for _, element := range existing.Elements() {
+			value, ok := element.(types.String)
+			if !ok || value.IsNull() || value.IsUnknown() {
+				continue
+			}
+			id, ok := resolved[value.ValueString()]
+			if !ok || !attached[id] || matched[id] {
+				continue
+			}
+			matched[id] = true
+			values = append(values, value)
+		}
+	}
+
+	for _, id := range apiSecrets {
+		if matched[id] {
+			continue
+		}
+		matched[id] = true
+		values = append(values, types.StringValue(id))
+	}
+
+	tfList, tfDiags := types.ListValue(types.StringType, values)
+	diags.Append(tfDiags...)
+	if diags.HasError() {
+		return nil
+	}
+
+	return &tfList
+}
+
 func (r *StackResource) flattenStackComponentsToTFMap(
 	ctx context.Context,
 	apiComponents map[string][]ComponentResponse,
@@ -390,21 +597,45 @@ func (r *StackResource) Create(ctx context.Context, req resource.CreateRequest,
 		return
 	}
 
-	labels := expandStringMapFromTF(ctx, data.Labels, &resp.Diagnostics)
+	labels := expandLabelsFromTF(ctx, r.client, data.Labels, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	environment := expandStringMapFromTF(ctx, data.Environment, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	secrets := r.expandStackSecretIDs(ctx, data.Secrets, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
 	stackReq := StackRequest{
-		Name:       data.Name.ValueString(),
-		Components: components,
-		Labels:     labels,
+		Name:        data.Name.ValueString(),
+		Components:  components,
+		Labels:      labels,
+		Environment: environment,
+		Secrets:     secrets,
 	}
 
 	tflog.Trace(ctx, "creating stack")
 
 	stack, err := r.client.CreateStack(ctx, stackReq)
-	if err != nil {
+	if errors.Is(err, ErrConflict) && shouldAdoptExisting(r.client, data.AdoptExisting) {
+		stack, err = r.adoptExistingStack(ctx, stackReq, isAdditiveLabels(data.LabelsMode))
+		if err != nil {
+			resp.Diagnostics.AddError("Client Error",
+				fmt.Sprintf("Unable to adopt existing stack %q, got error: %s", stackReq.Name, err))
+			return
+		}
+		resp.Diagnostics.AddWarning(
+			"Adopted Existing Stack",
+			fmt.Sprintf("Stack '%s' already existed and was adopted with ID %s. It was updated to "+
+				"match the configuration and is now managed by Terraform.", stack.Name, stack.ID),
+		)
+	} else if err != nil {
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to create stack, got error: %s", err))
 		return
 	}
@@ -416,13 +647,19 @@ func (r *StackResource) Create(ctx context.Context, req resource.CreateRequest,
 
 	tflog.Trace(ctx, "created a stack")
 
+	if resp.Private != nil {
+		recordProviderLabels(ctx, resp.Private, r.client, &resp.Diagnostics)
+	}
+
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	setResourceIdentity(ctx, r.client, data.ID.ValueString(), resp.Identity, &resp.Diagnostics)
 }
 
 func (r *StackResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
 	var data StackResourceModel
 
 	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	checkResourceIdentity(ctx, r.client, "stack", data.ID.ValueString(), req.Identity, &resp.Diagnostics)
 
 	if resp.Diagnostics.HasError() {
 		return
@@ -446,12 +683,14 @@ func (r *StackResource) Read(ctx context.Context, req resource.ReadRequest, resp
 	}
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	setResourceIdentity(ctx, r.client, data.ID.ValueString(), resp.Identity, &resp.Diagnostics)
 }
 
 func (r *StackResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
-	var data StackResourceModel
+	var data, state StackResourceModel
 
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
 
 	if resp.Diagnostics.HasError() {
 		return
@@ -462,15 +701,53 @@ func (r *StackResource) Update(ctx context.Context, req resource.UpdateRequest,
 		return
 	}
 
-	labels := expandStringMapFromTF(ctx, data.Labels, &resp.Diagnostics)
+	labels := expandLabelsFromTF(ctx, r.client, data.Labels, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
+	if isAdditiveLabels(data.LabelsMode) {
+		current, err := r.client.GetStack(ctx, data.ID.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read stack labels, got error: %s", err))
+			return
+		}
+		if current != nil && current.Metadata != nil {
+			labels = additiveLabels(current.Metadata.Labels, state.Labels, appliedProviderLabels(ctx, req.Private, &resp.Diagnostics), labels)
+		}
+	}
+
+	environment := expandStringMapFromTF(ctx, data.Environment, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	secrets := r.expandStackSecretIDs(ctx, data.Secrets, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Secrets that no longer exist cannot be attached to the stack anymore,
+	// so only the ones that still resolve need to be considered for removal.
+	currentSecrets := expandStringListFromTF(ctx, state.Secrets, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	currentResolved, err := r.resolveSecretIDs(ctx, currentSecrets)
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to resolve stack secrets, got error: %s", err))
+		return
+	}
+
+	addSecrets, removeSecrets := diffStackSecretIDs(currentResolved, secrets)
+
 	updateReq := StackUpdate{
-		Name:       data.Name.ValueString(),
-		Components: components,
-		Labels:     labels,
+		Name:          data.Name.ValueString(),
+		Components:    components,
+		Labels:        labels,
+		Environment:   environment,
+		AddSecrets:    addSecrets,
+		RemoveSecrets: removeSecrets,
 	}
 
 	tflog.Trace(ctx, "updating stack")
@@ -486,7 +763,85 @@ func (r *StackResource) Update(ctx context.Context, req resource.UpdateRequest,
 		return
 	}
 
+	if resp.Private != nil {
+		recordProviderLabels(ctx, resp.Private, r.client, &resp.Diagnostics)
+	}
+
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	setResourceIdentity(ctx, r.client, data.ID.ValueString(), resp.Identity, &resp.Diagnostics)
+}
+
+// adoptExistingStack updates the existing stack with the name of the given
+// request to match it, after its creation failed on a name conflict. In
+// additive label mode, the labels of the existing stack are kept.
+func (r *StackResource) adoptExistingStack(ctx context.Context, stackReq StackRequest, additive bool) (*StackResponse, error) {
+	id, err := findExistingID(ctx, "stack", stackReq.Name,
+		map[string]string{"name": stackReq.Name},
+		r.client.ListStacks,
+		func(s StackResponse) string { return s.ID },
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := r.client.GetStack(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if existing == nil {
+		return nil, fmt.Errorf("stack %s was deleted while it was being adopted", id)
+	}
+
+	current := make(map[string]string)
+	if existing.Metadata != nil {
+		for _, secretID := range existing.Metadata.Secrets {
+			current[secretID] = secretID
+		}
+		if additive {
+			stackReq.Labels = additiveLabels(existing.Metadata.Labels, types.MapNull(types.StringType), nil, stackReq.Labels)
+		}
+	}
+	addSecrets, removeSecrets := diffStackSecretIDs(current, stackReq.Secrets)
+
+	tflog.Trace(ctx, "adopting existing stack", map[string]any{"id": id})
+
+	return r.client.UpdateStack(ctx, id, StackUpdate{
+		Name:          stackReq.Name,
+		Components:    stackReq.Components,
+		Labels:        stackReq.Labels,
+		Environment:   stackReq.Environment,
+		AddSecrets:    addSecrets,
+		RemoveSecrets: removeSecrets,
+	})
+}
+
+// diffStackSecretIDs returns the secret IDs that have to be attached to and
+// detached from a stack to go from the current to the desired set of secrets.
+func diffStackSecretIDs(current map[string]string, desired []string) ([]string, []string) {
+	currentIDs := make(map[string]bool, len(current))
+	for _, id := range current {
+		currentIDs[id] = true
+	}
+	desiredIDs := make(map[string]bool, len(desired))
+	for _, id := range desired {
+		desiredIDs[id] = true
+	}
+
+	var add, remove []string
+	for _, id := range desired {
+		if !currentIDs[id] {
+			add = append(add, id)
+			currentIDs[id] = true
+		}
+	}
+	for id := range currentIDs {
+		if !desiredIDs[id] {
+			remove = append(remove, id)
+		}
+	}
+	sort.Strings(remove)
+
+	return add, remove
 }
 
 func (r *StackResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
@@ -498,6 +853,11 @@ func (r *StackResource) Delete(ctx context.Context, req resource.DeleteRequest,
 		return
 	}
 
+	if isDeletionProtected(data.DeletionProtection) {
+		addDeletionProtectionError(&resp.Diagnostics, "stack", data.Name.ValueString(), "delete")
+		return
+	}
+
 	tflog.Trace(ctx, "deleting stack")
 
 	err := r.client.DeleteStack(ctx, data.ID.ValueString())
@@ -507,6 +867,40 @@ func (r *StackResource) Delete(ctx context.Context, req resource.DeleteRequest,
 	}
 }
 
+func (r *StackResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	replace := false
+	if !req.State.Raw.IsNull() && !req.Plan.Raw.IsNull() {
+		var stateComponents, planComponents types.Map
+		resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("components"), &stateComponents)...)
+		resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("components"), &planComponents)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		modifyResp := &planmodifier.MapResponse{}
+		requiresReplaceIfRequiredComponentChanges{}.PlanModifyMap(ctx, planmodifier.MapRequest{
+			StateValue: stateComponents,
+			PlanValue:  planComponents,
+		}, modifyResp)
+		resp.Diagnostics.Append(modifyResp.Diagnostics...)
+		replace = modifyResp.RequiresReplace
+	}
+
+	checkDeletionProtection(ctx, req, resp, "stack", "name", replace)
+	if resp.Diagnostics.HasError() || req.Plan.Raw.IsNull() {
+		return
+	}
+
+	planLabelsAll(ctx, r.client, req, resp)
+}
+
 func (r *StackResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+	if !importStateFromIdentity(ctx, r.client, "stack", req, resp) {
+		importStateByNameOrID(ctx, req, resp, "stack",
+			map[string]string{"name": req.ID},
+			r.client.ListStacks,
+			func(s StackResponse) string { return s.ID },
+		)
+	}
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("deletion_protection"), false)...)
 }
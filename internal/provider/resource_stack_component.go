@@ -3,10 +3,12 @@ package provider
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sort"
+	"strings"
 
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
-	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
@@ -20,7 +22,10 @@ import (
 
 var _ resource.Resource = &StackComponentResource{}
 var _ resource.ResourceWithImportState = &StackComponentResource{}
+var _ resource.ResourceWithIdentity = &StackComponentResource{}
+var _ resource.ResourceWithModifyPlan = &StackComponentResource{}
 var _ resource.ResourceWithConfigValidators = &StackComponentResource{}
+var _ resource.ResourceWithUpgradeState = &StackComponentResource{}
 
 func NewStackComponentResource() resource.Resource {
 	return &StackComponentResource{}
@@ -28,28 +33,51 @@ func NewStackComponentResource() resource.Resource {
 
 type StackComponentResource struct {
 	client *Client
+
+	// configCodec converts configuration values for typed flavor resources.
+	// The generic resource sends configuration values as strings.
+	configCodec componentConfigCodec
+}
+
+// componentConfigCodec converts configuration values between the strings held
+// in the configuration map and the values exchanged with the ZenML API.
+type componentConfigCodec interface {
+	encodeConfigValue(key, value string) (interface{}, error)
+	decodeConfigValue(key string, value interface{}) (string, bool)
 }
 
 type StackComponentResourceModel struct {
-	ID                  types.String `tfsdk:"id"`
-	Name                types.String `tfsdk:"name"`
-	Type                types.String `tfsdk:"type"`
-	Flavor              types.String `tfsdk:"flavor"`
-	Configuration       types.Map    `tfsdk:"configuration"`
-	ConnectorID         types.String `tfsdk:"connector_id"`
-	ConnectorResourceID types.String `tfsdk:"connector_resource_id"`
-	Labels              types.Map    `tfsdk:"labels"`
-	Created             types.String `tfsdk:"created"`
-	Updated             types.String `tfsdk:"updated"`
+	ID                        types.String `tfsdk:"id"`
+	Name                      types.String `tfsdk:"name"`
+	Type                      types.String `tfsdk:"type"`
+	Flavor                    types.String `tfsdk:"flavor"`
+	Configuration             types.Map    `tfsdk:"configuration"`
+	SecretConfiguration       types.Map    `tfsdk:"secret_configuration"`
+	EffectiveConfiguration    types.Map    `tfsdk:"effective_configuration"`
+	ConnectorID               types.String `tfsdk:"connector_id"`
+	ConnectorResourceID       types.String `tfsdk:"connector_resource_id"`
+	ValidateConnectorResource types.Bool   `tfsdk:"validate_connector_resource"`
+	Labels                    types.Map    `tfsdk:"labels"`
+	LabelsAll                 types.Map    `tfsdk:"labels_all"`
+	LabelsMode                types.String `tfsdk:"labels_mode"`
+	Created                   types.String `tfsdk:"created"`
+	Updated                   types.String `tfsdk:"updated"`
+	AdoptExisting             types.Bool   `tfsdk:"adopt_existing"`
+	DeletionProtection        types.Bool   `tfsdk:"deletion_protection"`
 }
 
 func (r *StackComponentResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
 	resp.TypeName = req.ProviderTypeName + "_stack_component"
 }
 
+func (r *StackComponentResource) IdentitySchema(ctx context.Context, req resource.IdentitySchemaRequest, resp *resource.IdentitySchemaResponse) {
+	resp.IdentitySchema = resourceIdentitySchema("stack component")
+}
+
 func (r *StackComponentResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
 		MarkdownDescription: "Stack component resource",
+		Version:             1,
 
 		Attributes: map[string]schema.Attribute{
 			"id": schema.StringAttribute{
@@ -81,30 +109,48 @@ func (r *StackComponentResource) Schema(ctx context.Context, req resource.Schema
 				},
 			},
 			"configuration": schema.MapAttribute{
-				MarkdownDescription: "Configuration for the stack component",
-				ElementType:         types.StringType,
-				Optional:            true,
-				Sensitive:           true,
+				MarkdownDescription: "Configuration for the stack component. Values are shown in plans, " +
+					"use `secret_configuration` for credentials.",
+				ElementType: types.StringType,
+				Optional:    true,
+			},
+			"secret_configuration": schema.MapAttribute{
+				MarkdownDescription: "Sensitive configuration for the stack component, such as credentials. " +
+					"It is sent to the server together with `configuration` and masked in plans.",
+				ElementType: types.StringType,
+				Optional:    true,
+				Sensitive:   true,
+			},
+			"effective_configuration": schema.MapAttribute{
+				MarkdownDescription: "Complete configuration of the stack component on the server, " +
+					"including the defaults of the flavor. Changes to keys that are not set " +
+					"in `configuration` are not reported as drift.",
+				ElementType: types.StringType,
+				Computed:    true,
+				Sensitive:   true,
 			},
 			"connector_id": schema.StringAttribute{
-				MarkdownDescription: "ID of the service connector to use for this component",
-				Optional:            true,
-				// We cannot delete service connectors while they are still in
-				// use by a component, so we need to force new components when
-				// the connector is changed.
-				PlanModifiers: []planmodifier.String{
-					stringplanmodifier.RequiresReplace(),
-				},
+				MarkdownDescription: "ID of the service connector to use for this component. " +
+					"Changing it updates the component in place.",
+				Optional: true,
 			},
 			"connector_resource_id": schema.StringAttribute{
 				MarkdownDescription: "Resource ID to use from the service connector",
 				Optional:            true,
 			},
+			"validate_connector_resource": schema.BoolAttribute{
+				MarkdownDescription: "Whether to check during plan that the service connector can access " +
+					"`connector_resource_id`. The plan fails with the resources the connector can access " +
+					"if it cannot.",
+				Optional: true,
+			},
 			"labels": schema.MapAttribute{
 				MarkdownDescription: "Labels for the stack component",
 				ElementType:         types.StringType,
 				Optional:            true,
 			},
+			"labels_all":  labelsAllAttribute(),
+			"labels_mode": labelsModeAttribute(),
 			"created": schema.StringAttribute{
 				MarkdownDescription: "The timestamp when the stack component was created",
 				Computed:            true,
@@ -113,6 +159,8 @@ func (r *StackComponentResource) Schema(ctx context.Context, req resource.Schema
 				MarkdownDescription: "The timestamp when the stack component was last updated",
 				Computed:            true,
 			},
+			"adopt_existing":      adoptExistingAttribute(),
+			"deletion_protection": deletionProtectionAttribute(),
 		},
 	}
 }
@@ -120,6 +168,8 @@ func (r *StackComponentResource) Schema(ctx context.Context, req resource.Schema
 func (r *StackComponentResource) ConfigValidators(ctx context.Context) []resource.ConfigValidator {
 	return []resource.ConfigValidator{
 		&stackComponentConfigValidator{},
+		secretConfigurationValidator{},
+		credentialConfigurationValidator{},
 	}
 }
 
@@ -157,6 +207,13 @@ func (v stackComponentConfigValidator) ValidateResource(ctx context.Context, req
 	}
 }
 
+func (r *StackComponentResource) UpgradeState(ctx context.Context) map[int64]resource.StateUpgrader {
+	return map[int64]resource.StateUpgrader{
+		// Version 0 states predate deletion_protection.
+		0: rawStateUpgrader(setStateDefault("deletion_protection", false)),
+	}
+}
+
 func (r *StackComponentResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
 	// Prevent panic if the provider has not been configured.
 	if req.ProviderData == nil {
@@ -182,56 +239,39 @@ func (r *StackComponentResource) populateStackComponentModel(
 	component *ComponentResponse,
 	data *StackComponentResourceModel,
 	diags *diag.Diagnostics,
-	updateConfiguration bool,
+	refresh configurationRefresh,
 ) {
 	data.ID = types.StringValue(component.ID)
 	data.Name = types.StringValue(component.Name)
 
-	var oldUpdated string
 	if component.Body != nil {
 		data.Type = types.StringValue(component.Body.Type)
 		data.Flavor = types.StringValue(component.Body.Flavor)
 		data.Created = types.StringValue(component.Body.Created)
-
-		if !data.Updated.IsNull() {
-			oldUpdated = data.Updated.ValueString()
-		}
 		data.Updated = types.StringValue(component.Body.Updated)
 	}
 
+	data.EffectiveConfiguration = types.MapNull(types.StringType)
 	if component.Metadata != nil {
-		if len(component.Metadata.Configuration) > 0 {
-			timestampUnchanged := oldUpdated != "" &&
-				oldUpdated == component.Body.Updated
-
-			if updateConfiguration && !timestampUnchanged {
-				cfg, changed := MergeOrCompareConfiguration(
-					ctx,
-					data.Configuration,
-					component.Metadata.Configuration,
-					diags,
-				)
-				if !diags.HasError() && changed {
-					data.Configuration = cfg
-				}
-			}
-		} else if !data.Configuration.IsNull() && len(data.Configuration.Elements()) > 0 {
-			data.Configuration = types.MapNull(types.StringType)
+		serverConfiguration := r.stateConfiguration(component.Metadata.Configuration)
+		data.Configuration = ReconcileConfiguration(ctx, data.Configuration, serverConfiguration, nil, refresh, diags)
+		if refresh == configurationAdopt {
+			// Nothing tells secret values apart on import, so all keys
+			// start out in configuration.
+			data.SecretConfiguration = types.MapNull(types.StringType)
+		} else {
+			data.SecretConfiguration = ReconcileConfiguration(ctx, data.SecretConfiguration, serverConfiguration, nil, refresh, diags)
+		}
+		if refresh == configurationKeep {
+			WarnIgnoredConfiguration(data.Configuration, serverConfiguration, diags)
+			WarnIgnoredConfiguration(data.SecretConfiguration, serverConfiguration, diags)
 		}
+		data.EffectiveConfiguration = EffectiveConfiguration(ctx, component.Metadata.Configuration, diags)
 
-		if len(component.Metadata.Labels) > 0 {
-			labelMap := make(map[string]attr.Value)
-			for k, v := range component.Metadata.Labels {
-				labelMap[k] = types.StringValue(v)
-			}
-			labelValue, labelDiags := types.MapValue(types.StringType, labelMap)
-			diags.Append(labelDiags...)
-			if !diags.HasError() {
-				data.Labels = labelValue
-			}
-		} else if !data.Labels.IsNull() && len(data.Labels.Elements()) > 0 {
-			data.Labels = types.MapNull(types.StringType)
+		if labelValue := flattenLabelsToTFMap(r.client, component.Metadata.Labels, data.Labels, isAdditiveLabels(data.LabelsMode), diags); labelValue != nil {
+			data.Labels = *labelValue
 		}
+		data.LabelsAll = labelsAllValue(component.Metadata.Labels, diags)
 
 		if component.Metadata.Connector != nil {
 			data.ConnectorID = types.StringValue(component.Metadata.Connector.ID)
@@ -247,6 +287,72 @@ func (r *StackComponentResource) populateStackComponentModel(
 	}
 }
 
+// apiConfiguration converts the configuration maps of a stack component model
+// to the configuration sent to the ZenML API. The ZenML API does not separate
+// secret values, so both maps are sent as one configuration.
+func (r *StackComponentResource) apiConfiguration(
+	ctx context.Context,
+	data *StackComponentResourceModel,
+	diags *diag.Diagnostics,
+) map[string]interface{} {
+	configuration := make(map[string]interface{})
+
+	configElements := make(map[string]types.String)
+	for _, value := range []types.Map{data.Configuration, data.SecretConfiguration} {
+		if value.IsNull() || value.IsUnknown() {
+			continue
+		}
+		elements := make(map[string]types.String, len(value.Elements()))
+		diags.Append(value.ElementsAs(ctx, &elements, false)...)
+		for k, v := range elements {
+			configElements[k] = v
+		}
+	}
+	if diags.HasError() {
+		return configuration
+	}
+
+	for k, v := range configElements {
+		if r.configCodec == nil {
+			// Always treat configuration values as strings
+			// The ZenML API expects string values, and any JSON encoding
+			// should be handled by the user in their Terraform configuration
+			configuration[k] = v.ValueString()
+			continue
+		}
+
+		encoded, err := r.configCodec.encodeConfigValue(k, v.ValueString())
+		if err != nil {
+			diags.AddError(
+				"Invalid Configuration Value",
+				fmt.Sprintf("Unable to convert configuration value %q: %s", k, err),
+			)
+			continue
+		}
+		configuration[k] = encoded
+	}
+
+	return configuration
+}
+
+// stateConfiguration converts the configuration returned by the ZenML API to
+// the values held in the configuration map.
+func (r *StackComponentResource) stateConfiguration(raw map[string]interface{}) map[string]interface{} {
+	if r.configCodec == nil {
+		return raw
+	}
+
+	configuration := make(map[string]interface{}, len(raw))
+	for k, v := range raw {
+		if v == nil {
+			configuration[k] = nil
+		} else if decoded, ok := r.configCodec.decodeConfigValue(k, v); ok {
+			configuration[k] = decoded
+		}
+	}
+	return configuration
+}
+
 func (r *StackComponentResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
 	var data StackComponentResourceModel
 
@@ -262,33 +368,14 @@ func (r *StackComponentResource) Create(ctx context.Context, req resource.Create
 		return
 	}
 
-	configuration := make(map[string]interface{})
-	if !data.Configuration.IsNull() {
-		configElements := make(map[string]types.String, len(data.Configuration.Elements()))
-		resp.Diagnostics.Append(data.Configuration.ElementsAs(ctx, &configElements, false)...)
-		if resp.Diagnostics.HasError() {
-			return
-		}
-
-		for k, v := range configElements {
-			// Always treat configuration values as strings
-			// The ZenML API expects string values, and any JSON encoding
-			// should be handled by the user in their Terraform configuration
-			configuration[k] = v.ValueString()
-		}
+	configuration := r.apiConfiguration(ctx, &data, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
 	}
 
-	labels := make(map[string]string)
-	if !data.Labels.IsNull() {
-		labelElements := make(map[string]types.String, len(data.Labels.Elements()))
-		resp.Diagnostics.Append(data.Labels.ElementsAs(ctx, &labelElements, false)...)
-		if resp.Diagnostics.HasError() {
-			return
-		}
-
-		for k, v := range labelElements {
-			labels[k] = v.ValueString()
-		}
+	labels := expandLabelsFromTF(ctx, r.client, data.Labels, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
 	}
 
 	componentReq := ComponentRequest{
@@ -313,25 +400,96 @@ func (r *StackComponentResource) Create(ctx context.Context, req resource.Create
 	tflog.Trace(ctx, "creating stack component")
 
 	component, err := r.client.CreateComponent(ctx, componentReq)
-	if err != nil {
+	if errors.Is(err, ErrConflict) && shouldAdoptExisting(r.client, data.AdoptExisting) {
+		component, err = r.adoptExistingComponent(ctx, componentReq, isAdditiveLabels(data.LabelsMode))
+		if err != nil {
+			resp.Diagnostics.AddError("Client Error",
+				fmt.Sprintf("Unable to adopt existing stack component %q, got error: %s", componentReq.Name, err))
+			return
+		}
+		resp.Diagnostics.AddWarning(
+			"Adopted Existing Stack Component",
+			fmt.Sprintf("Stack component '%s' already existed and was adopted with ID %s. It was updated "+
+				"to match the configuration and is now managed by Terraform.", component.Name, component.ID),
+		)
+	} else if err != nil {
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to create stack component, got error: %s", err))
 		return
 	}
 
-	r.populateStackComponentModel(ctx, component, &data, &resp.Diagnostics, false)
+	r.populateStackComponentModel(ctx, component, &data, &resp.Diagnostics, configurationKeep)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
 	tflog.Trace(ctx, "created a stack component")
 
+	if resp.Private != nil {
+		recordProviderLabels(ctx, resp.Private, r.client, &resp.Diagnostics)
+	}
+
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	setResourceIdentity(ctx, r.client, data.ID.ValueString(), resp.Identity, &resp.Diagnostics)
+}
+
+// adoptExistingComponent updates the existing component with the name and type
+// of the given request to match it, after its creation failed on a name
+// conflict. Components of another flavor are not adopted, because the flavor
+// of a component cannot be changed. In additive label mode, the labels of the
+// existing component are kept.
+func (r *StackComponentResource) adoptExistingComponent(
+	ctx context.Context,
+	componentReq ComponentRequest,
+	additive bool,
+) (*ComponentResponse, error) {
+	id, err := findExistingID(ctx, "stack component", componentReq.Name,
+		map[string]string{"name": componentReq.Name, "type": componentReq.Type},
+		r.client.ListStackComponents,
+		func(c ComponentResponse) string { return c.ID },
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := r.client.GetComponent(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if existing == nil {
+		return nil, fmt.Errorf("stack component %s was deleted while it was being adopted", id)
+	}
+	if existing.Body != nil {
+		if existing.Body.Type != componentReq.Type || existing.Body.Flavor != componentReq.Flavor {
+			return nil, fmt.Errorf(
+				"the existing component %s is a %s of flavor %q, not a %s of flavor %q; "+
+					"rename the component or delete the existing one",
+				id, existing.Body.Type, existing.Body.Flavor, componentReq.Type, componentReq.Flavor,
+			)
+		}
+	}
+
+	if additive && existing.Metadata != nil {
+		componentReq.Labels = additiveLabels(existing.Metadata.Labels, types.MapNull(types.StringType), nil, componentReq.Labels)
+	}
+
+	tflog.Trace(ctx, "adopting existing stack component", map[string]any{"id": id})
+
+	return r.client.UpdateComponent(ctx, id, ComponentUpdate{
+		Name:                componentReq.Name,
+		Type:                componentReq.Type,
+		Flavor:              componentReq.Flavor,
+		Configuration:       componentReq.Configuration,
+		ConnectorID:         componentReq.ConnectorID,
+		ConnectorResourceID: componentReq.ConnectorResourceID,
+		Labels:              componentReq.Labels,
+	})
 }
 
 func (r *StackComponentResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
 	var data StackComponentResourceModel
 
 	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	checkResourceIdentity(ctx, r.client, "stack component", data.ID.ValueString(), req.Identity, &resp.Diagnostics)
 
 	if resp.Diagnostics.HasError() {
 		return
@@ -349,49 +507,44 @@ func (r *StackComponentResource) Read(ctx context.Context, req resource.ReadRequ
 		return
 	}
 
-	r.populateStackComponentModel(ctx, component, &data, &resp.Diagnostics, true)
+	refresh := readConfigurationRefresh(ctx, req, resp)
+	r.populateStackComponentModel(ctx, component, &data, &resp.Diagnostics, refresh)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	setResourceIdentity(ctx, r.client, data.ID.ValueString(), resp.Identity, &resp.Diagnostics)
 }
 
 func (r *StackComponentResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
-	var data StackComponentResourceModel
+	var data, state StackComponentResourceModel
 
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
 
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	configuration := make(map[string]interface{})
-	if !data.Configuration.IsNull() {
-		configElements := make(map[string]types.String, len(data.Configuration.Elements()))
-		resp.Diagnostics.Append(data.Configuration.ElementsAs(ctx, &configElements, false)...)
-		if resp.Diagnostics.HasError() {
-			return
-		}
+	configuration := r.apiConfiguration(ctx, &data, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
-		for k, v := range configElements {
-			// Always treat configuration values as strings
-			// The ZenML API expects string values, and any JSON encoding
-			// should be handled by the user in their Terraform configuration
-			configuration[k] = v.ValueString()
-		}
+	labels := expandLabelsFromTF(ctx, r.client, data.Labels, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
 	}
 
-	labels := make(map[string]string)
-	if !data.Labels.IsNull() {
-		labelElements := make(map[string]types.String, len(data.Labels.Elements()))
-		resp.Diagnostics.Append(data.Labels.ElementsAs(ctx, &labelElements, false)...)
-		if resp.Diagnostics.HasError() {
+	if isAdditiveLabels(data.LabelsMode) {
+		current, err := r.client.GetComponent(ctx, data.ID.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read stack component labels, got error: %s", err))
 			return
 		}
-
-		for k, v := range labelElements {
-			labels[k] = v.ValueString()
+		if current != nil && current.Metadata != nil {
+			labels = additiveLabels(current.Metadata.Labels, state.Labels, appliedProviderLabels(ctx, req.Private, &resp.Diagnostics), labels)
 		}
 	}
 
@@ -421,12 +574,17 @@ func (r *StackComponentResource) Update(ctx context.Context, req resource.Update
 		return
 	}
 
-	r.populateStackComponentModel(ctx, component, &data, &resp.Diagnostics, false)
+	r.populateStackComponentModel(ctx, component, &data, &resp.Diagnostics, configurationKeep)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
+	if resp.Private != nil {
+		recordProviderLabels(ctx, resp.Private, r.client, &resp.Diagnostics)
+	}
+
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	setResourceIdentity(ctx, r.client, data.ID.ValueString(), resp.Identity, &resp.Diagnostics)
 }
 
 func (r *StackComponentResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
@@ -438,6 +596,11 @@ func (r *StackComponentResource) Delete(ctx context.Context, req resource.Delete
 		return
 	}
 
+	if isDeletionProtected(data.DeletionProtection) {
+		addDeletionProtectionError(&resp.Diagnostics, "stack component", data.Name.ValueString(), "delete")
+		return
+	}
+
 	tflog.Trace(ctx, "deleting stack component")
 
 	stacks, err := r.client.ListStacksByComponent(ctx, data.ID.ValueString())
@@ -508,6 +671,122 @@ func (r *StackComponentResource) Delete(ctx context.Context, req resource.Delete
 	}
 }
 
+func (r *StackComponentResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	replace := false
+	if !req.State.Raw.IsNull() && !req.Plan.Raw.IsNull() {
+		replace = planChangesAttributes(ctx, req, &resp.Diagnostics, path.Root("type"), path.Root("flavor"))
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	checkDeletionProtection(ctx, req, resp, "stack component", "name", replace)
+	if resp.Diagnostics.HasError() || req.Plan.Raw.IsNull() {
+		return
+	}
+
+	if planLabelsAll(ctx, r.client, req, resp) {
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("updated"), types.StringUnknown())...)
+	}
+
+	var data StackComponentResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() || !data.ValidateConnectorResource.ValueBool() {
+		return
+	}
+	r.validateConnectorResource(ctx, data.Type.ValueString(), data.Flavor.ValueString(),
+		data.ConnectorID, data.ConnectorResourceID, &resp.Diagnostics)
+}
+
+// validateConnectorResource checks that a service connector can access the
+// resource a component uses, by verifying the connector for the resource type
+// of the component's flavor. If it cannot, it fails with the resources the
+// connector can access.
+func (r *StackComponentResource) validateConnectorResource(
+	ctx context.Context,
+	componentType string,
+	flavor string,
+	connectorID types.String,
+	connectorResourceID types.String,
+	diags *diag.Diagnostics,
+) {
+	if connectorID.IsNull() || connectorID.IsUnknown() || connectorResourceID.IsNull() ||
+		connectorResourceID.IsUnknown() || componentType == "" || flavor == "" {
+		return
+	}
+
+	// The connector resource type is part of the flavor metadata, which the
+	// server only returns for hydrated flavors.
+	flavors, err := r.client.ListFlavors(ctx, &ListParams{
+		Filter: map[string]string{"name": flavor, "type": componentType, "hydrate": "true"},
+	})
+	if err != nil {
+		diags.AddError("Client Error",
+			fmt.Sprintf("Unable to look up flavor %s, got error: %s", flavor, err))
+		return
+	}
+	var resourceType string
+	for _, f := range flavors.Items {
+		if f.Metadata != nil && f.Metadata.ConnectorResourceType != nil {
+			resourceType = *f.Metadata.ConnectorResourceType
+		}
+	}
+	if resourceType == "" {
+		diags.AddAttributeWarning(
+			path.Root("validate_connector_resource"),
+			"Connector Resource Not Validated",
+			fmt.Sprintf("The %s flavor of %s components does not declare a service connector "+
+				"resource type, so connector_resource_id cannot be validated.", flavor, componentType),
+		)
+		return
+	}
+
+	resourceID := connectorResourceID.ValueString()
+	result, err := r.client.VerifyExistingServiceConnector(ctx, connectorID.ValueString(), resourceType, resourceID)
+	if err != nil {
+		diags.AddError("Client Error",
+			fmt.Sprintf("Unable to verify service connector, got error: %s", err))
+		return
+	}
+	if result.Error == nil {
+		return
+	}
+
+	// List the resources the connector can access to help fixing the ID.
+	available, err := r.client.VerifyExistingServiceConnector(ctx, connectorID.ValueString(), resourceType, "")
+	var resourceIDs []string
+	if err == nil {
+		for _, resources := range available.Resources {
+			if resources.ResourceType == resourceType {
+				resourceIDs = append(resourceIDs, resources.ResourceIDs...)
+			}
+		}
+	}
+
+	detail := fmt.Sprintf("The service connector cannot access the %s resource %q: %s",
+		resourceType, resourceID, *result.Error)
+	if len(resourceIDs) > 0 {
+		sort.Strings(resourceIDs)
+		detail += fmt.Sprintf("\n\nThe connector can access the following %s resources:\n\n  - %s",
+			resourceType, strings.Join(resourceIDs, "\n  - "))
+	}
+	diags.AddAttributeError(path.Root("connector_resource_id"), "Unreachable Connector Resource", detail)
+}
+
 func (r *StackComponentResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+	if !importStateFromIdentity(ctx, r.client, "stack component", req, resp) {
+		componentType, name := parseComponentImportID(req.ID)
+		filter := map[string]string{"name": name}
+		if componentType != "" {
+			filter["type"] = componentType
+		}
+
+		importStateByNameOrID(ctx, req, resp, "stack component",
+			filter,
+			r.client.ListStackComponents,
+			func(c ComponentResponse) string { return c.ID },
+		)
+	}
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("deletion_protection"), false)...)
+	markImported(ctx, resp)
 }
@@ -1,9 +1,17 @@
 package provider
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
 )
 
@@ -30,6 +38,12 @@ func TestAccStackComponent_basic(t *testing.T) {
 				ImportState:       true,
 				ImportStateVerify: true,
 			},
+			{
+				ResourceName:      "zenml_stack_component.test",
+				ImportState:       true,
+				ImportStateId:     "artifact_store/test-store",
+				ImportStateVerify: true,
+			},
 		},
 	})
 }
@@ -95,6 +109,120 @@ func TestAccStackComponent_withConfiguration(t *testing.T) {
 	})
 }
 
+func TestStackComponentValidateConnectorResource(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		var response interface{}
+		switch req.URL.Path {
+		case "/api/v1/flavors":
+			// The metadata holding the connector resource type is only
+			// returned for hydrated flavors.
+			if req.URL.Query().Get("hydrate") != "true" {
+				http.Error(w, "flavors must be hydrated", http.StatusBadRequest)
+				return
+			}
+			resourceType := "gcs-bucket"
+			response = Page[FlavorResponse]{Index: 1, TotalPages: 1, Total: 1, Items: []FlavorResponse{{
+				Name:     "gcp",
+				Metadata: &FlavorResponseMetadata{ConnectorResourceType: &resourceType},
+			}}}
+		case "/api/v1/service_connectors/connector-1/verify":
+			resources := ServiceConnectorResources{Resources: []ServiceConnectorTypedResources{{
+				ResourceType: req.URL.Query().Get("resource_type"),
+				ResourceIDs:  []string{"gs://models", "gs://artifacts"},
+			}}}
+			if id := req.URL.Query().Get("resource_id"); id != "" && id != "gs://artifacts" {
+				message := fmt.Sprintf("bucket %s not found", id)
+				resources.Error = &message
+			}
+			response = resources
+		default:
+			http.NotFound(w, req)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(response)
+	}))
+	defer server.Close()
+
+	r := &StackComponentResource{client: NewClient(server.URL, "", "test-token")}
+	ctx := context.Background()
+
+	var diags diag.Diagnostics
+	r.validateConnectorResource(ctx, "artifact_store", "gcp",
+		types.StringValue("connector-1"), types.StringValue("gs://artifacts"), &diags)
+	if diags.HasError() {
+		t.Errorf("expected an accessible resource to pass, got %v", diags)
+	}
+
+	r.validateConnectorResource(ctx, "artifact_store", "gcp",
+		types.StringValue("connector-1"), types.StringValue("gs://artifcats"), &diags)
+	if !diags.HasError() || !strings.Contains(diags.Errors()[0].Detail(), "  - gs://artifacts\n  - gs://models") {
+		t.Errorf("expected an error listing the accessible buckets, got %v", diags)
+	}
+}
+
+func TestStackComponentAdoptExisting(t *testing.T) {
+	var update ComponentUpdate
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		existing := ComponentResponse{
+			ID:   "component-1",
+			Name: "artifacts",
+			Body: &ComponentResponseBody{Type: "artifact_store", Flavor: "gcp"},
+		}
+		var response interface{}
+		switch {
+		case req.URL.Path == "/api/v1/components" && req.Method == http.MethodPost:
+			http.Error(w, `{"detail": "component already exists"}`, http.StatusConflict)
+			return
+		case req.URL.Path == "/api/v1/components":
+			if req.URL.Query().Get("name") != "artifacts" || req.URL.Query().Get("type") != "artifact_store" {
+				t.Errorf("unexpected component filter %s", req.URL.RawQuery)
+			}
+			response = Page[ComponentResponse]{Index: 1, TotalPages: 1, Total: 1, Items: []ComponentResponse{existing}}
+		case req.URL.Path == "/api/v1/components/component-1" && req.Method == http.MethodPut:
+			_ = json.NewDecoder(req.Body).Decode(&update)
+			response = existing
+		case req.URL.Path == "/api/v1/components/component-1":
+			response = existing
+		default:
+			http.NotFound(w, req)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(response)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "", "test-token")
+	r := &StackComponentResource{client: client}
+	ctx := context.Background()
+	componentReq := ComponentRequest{
+		Name:          "artifacts",
+		Type:          "artifact_store",
+		Flavor:        "gcp",
+		Configuration: map[string]interface{}{"path": "gs://artifacts"},
+	}
+
+	_, err := client.CreateComponent(ctx, componentReq)
+	if !errors.Is(err, ErrConflict) {
+		t.Fatalf("expected a conflict error, got %v", err)
+	}
+	if shouldAdoptExisting(client, types.BoolNull()) || !shouldAdoptExisting(client, types.BoolValue(true)) {
+		t.Errorf("expected the resource setting to override the provider default")
+	}
+
+	component, err := r.adoptExistingComponent(ctx, componentReq, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if component.ID != "component-1" || update.Configuration["path"] != "gs://artifacts" {
+		t.Errorf("expected the existing component to be updated, got %s with %v", component.ID, update.Configuration)
+	}
+
+	componentReq.Flavor = "s3"
+	if _, err := r.adoptExistingComponent(ctx, componentReq, false); err == nil || !strings.Contains(err.Error(), `flavor "gcp"`) {
+		t.Errorf("expected a component of another flavor not to be adopted, got %v", err)
+	}
+}
+
 func testAccStackComponentConfig_basic() string {
 	return fmt.Sprintf(`
 %s
@@ -227,3 +227,80 @@ func TestRequiresReplaceIfRequiredComponentChanges_NoChange(t *testing.T) {
 		t.Error("expected RequiresReplace=false when nothing changes")
 	}
 }
+
+func TestFlattenStackSecretsToTFList_KeepsNamesAndAppendsDrift(t *testing.T) {
+	ctx := context.Background()
+
+	existing, diags := types.ListValue(types.StringType, []attr.Value{
+		types.StringValue("aws-credentials"),
+		types.StringValue("deleted-secret"),
+	})
+	if diags.HasError() {
+		t.Fatalf("unexpected diagnostics creating existing list: %v", diags)
+	}
+
+	resolved := map[string]string{
+		"aws-credentials": "secret-1",
+	}
+
+	var testDiags diag.Diagnostics
+	gotPtr := flattenStackSecretsToTFList(
+		[]string{"secret-2", "secret-1"}, existing, resolved, &testDiags,
+	)
+	if testDiags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", testDiags)
+	}
+	if gotPtr == nil {
+		t.Fatal("expected flattened list, got nil")
+	}
+
+	var got []string
+	testDiags.Append(gotPtr.ElementsAs(ctx, &got, false)...)
+	if testDiags.HasError() {
+		t.Fatalf("unexpected diagnostics decoding output list: %v", testDiags)
+	}
+
+	want := []string{"aws-credentials", "secret-2"}
+	if len(got) != len(want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("expected %v, got %v", want, got)
+		}
+	}
+}
+
+func TestFlattenStackSecretsToTFList_EmptiesExistingSecrets(t *testing.T) {
+	existing, diags := types.ListValue(types.StringType, []attr.Value{
+		types.StringValue("aws-credentials"),
+	})
+	if diags.HasError() {
+		t.Fatalf("unexpected diagnostics creating existing list: %v", diags)
+	}
+
+	var testDiags diag.Diagnostics
+	gotPtr := flattenStackSecretsToTFList(nil, existing, map[string]string{}, &testDiags)
+	if testDiags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", testDiags)
+	}
+	if gotPtr == nil || !gotPtr.IsNull() {
+		t.Fatalf("expected null secrets list, got %v", gotPtr)
+	}
+}
+
+func TestDiffStackSecretIDs(t *testing.T) {
+	current := map[string]string{
+		"aws-credentials": "secret-1",
+		"secret-2":        "secret-2",
+	}
+
+	add, remove := diffStackSecretIDs(current, []string{"secret-1", "secret-3"})
+
+	if len(add) != 1 || add[0] != "secret-3" {
+		t.Fatalf("unexpected secrets to add: %v", add)
+	}
+	if len(remove) != 1 || remove[0] != "secret-2" {
+		t.Fatalf("unexpected secrets to remove: %v", remove)
+	}
+}
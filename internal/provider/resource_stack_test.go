@@ -6,6 +6,7 @@ import (
 
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
 	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"github.com/hashicorp/terraform-plugin-testing/tfversion"
 )
 
 func TestAccStack_basic(t *testing.T) {
@@ -33,6 +34,26 @@ func TestAccStack_basic(t *testing.T) {
 	})
 }
 
+func TestAccStack_importByIdentity(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_12_0),
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: testAccStackConfig_basic(),
+			},
+			{
+				ResourceName:    "zenml_stack.test",
+				ImportState:     true,
+				ImportStateKind: resource.ImportBlockWithResourceIdentity,
+			},
+		},
+	})
+}
+
 func TestAccStack_update(t *testing.T) {
 	var stackID string
 
@@ -135,6 +156,40 @@ func TestAccStack_updateOptionalComponentInPlace(t *testing.T) {
 	})
 }
 
+func TestAccStack_environmentAndSecrets(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccSecretPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccStackConfig_withEnvironmentAndSecrets("INFO"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(
+						"zenml_stack.test", "environment.LOG_LEVEL", "INFO"),
+					resource.TestCheckResourceAttr(
+						"zenml_stack.test", "secrets.#", "1"),
+					resource.TestCheckResourceAttrPair(
+						"zenml_stack.test", "secrets.0",
+						"zenml_secret.test", "name",
+					),
+				),
+			},
+			{
+				Config: testAccStackConfig_withEnvironmentAndSecrets("DEBUG"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(
+						"zenml_stack.test", "environment.LOG_LEVEL", "DEBUG"),
+				),
+			},
+			{
+				Config:             testAccStackConfig_withEnvironmentAndSecrets("DEBUG"),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: false,
+			},
+		},
+	})
+}
+
 func testAccCaptureResourceAttr(resourceName, attr string, dest *string) resource.TestCheckFunc {
 	return func(state *terraform.State) error {
 		rs, ok := state.RootModule().Resources[resourceName]
@@ -365,3 +420,44 @@ resource "zenml_stack" "test" {
 }
 `, testAccProviderConfig(), imageBuilderRef)
 }
+
+func testAccStackConfig_withEnvironmentAndSecrets(logLevel string) string {
+	return fmt.Sprintf(`
+%s
+
+resource "zenml_secret" "test" {
+  name = "test-stack-secret"
+
+  values = {
+    token = "test-token"
+  }
+}
+
+resource "zenml_stack_component" "artifact_store" {
+  name   = "test-store-env"
+  type   = "artifact_store"
+  flavor = "local"
+}
+
+resource "zenml_stack_component" "orchestrator" {
+  name   = "test-orchestrator-env"
+  type   = "orchestrator"
+  flavor = "local"
+}
+
+resource "zenml_stack" "test" {
+  name = "test-stack-env"
+
+  components = {
+    artifact_store = zenml_stack_component.artifact_store.id
+    orchestrator   = zenml_stack_component.orchestrator.id
+  }
+
+  environment = {
+    LOG_LEVEL = %q
+  }
+
+  secrets = [zenml_secret.test.name]
+}
+`, testAccProviderConfig(), logLevel)
+}
@@ -0,0 +1,100 @@
+// state_upgrade.go
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// stateTransform rewrites the attributes of a raw JSON state in place.
+type stateTransform func(attributes map[string]interface{})
+
+// setStateDefault sets an attribute that did not exist in a previous schema
+// version, or was left null, to the default of the current schema.
+func setStateDefault(name string, value interface{}) stateTransform {
+	return func(attributes map[string]interface{}) {
+		if attributes[name] == nil {
+			attributes[name] = value
+		}
+	}
+}
+
+// rawStateUpgrader returns a state upgrader that decodes the raw JSON state of
+// a previous schema version, applies the given transforms and decodes the
+// result with the current schema. Attributes that are no longer part of the
+// schema are dropped, and new attributes start out null unless a transform
+// sets them.
+//
+// The framework expects every upgrader to produce the current schema version
+// directly, so the upgrader of a version lists the transforms of all versions
+// that followed it.
+func rawStateUpgrader(transforms ...stateTransform) resource.StateUpgrader {
+	return resource.StateUpgrader{
+		StateUpgrader: func(ctx context.Context, req resource.UpgradeStateRequest, resp *resource.UpgradeStateResponse) {
+			upgradeRawState(ctx, req.RawState, &resp.State, transforms, &resp.Diagnostics)
+		},
+	}
+}
+
+func upgradeRawState(
+	ctx context.Context,
+	rawState *tfprotov6.RawState,
+	state *tfsdk.State,
+	transforms []stateTransform,
+	diags *diag.Diagnostics,
+) {
+	if rawState == nil || rawState.JSON == nil {
+		diags.AddError(
+			"Unable to Upgrade Resource State",
+			"The prior resource state is not available as JSON. Please report this issue to the provider developers.",
+		)
+		return
+	}
+
+	var attributes map[string]interface{}
+	if err := json.Unmarshal(rawState.JSON, &attributes); err != nil {
+		diags.AddError(
+			"Unable to Upgrade Resource State",
+			fmt.Sprintf("The prior resource state could not be decoded: %s", err),
+		)
+		return
+	}
+
+	for _, transform := range transforms {
+		transform(attributes)
+	}
+
+	upgraded, err := json.Marshal(attributes)
+	if err != nil {
+		diags.AddError(
+			"Unable to Upgrade Resource State",
+			fmt.Sprintf("The upgraded resource state could not be encoded: %s", err),
+		)
+		return
+	}
+
+	value, err := (&tfprotov6.RawState{JSON: upgraded}).UnmarshalWithOpts(
+		state.Schema.Type().TerraformType(ctx),
+		tfprotov6.UnmarshalOpts{
+			ValueFromJSONOpts: tftypes.ValueFromJSONOpts{
+				IgnoreUndefinedAttributes: true,
+			},
+		},
+	)
+	if err != nil {
+		diags.AddError(
+			"Unable to Upgrade Resource State",
+			fmt.Sprintf("The upgraded resource state does not match the current schema: %s", err),
+		)
+		return
+	}
+
+	state.Raw = value
+}
@@ -0,0 +1,132 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+)
+
+// TestUpgradeState_Fixtures upgrades a state fixture of every previous schema
+// version of every resource. Fixtures live in testdata/state_upgrade and are
+// named <resource type>_v<version>.json.
+func TestUpgradeState_Fixtures(t *testing.T) {
+	ctx := context.Background()
+
+	for _, newResource := range New("test")().Resources(ctx) {
+		r := newResource()
+
+		metadataResp := &resource.MetadataResponse{}
+		r.Metadata(ctx, resource.MetadataRequest{ProviderTypeName: "zenml"}, metadataResp)
+		schemaResp := &resource.SchemaResponse{}
+		r.Schema(ctx, resource.SchemaRequest{}, schemaResp)
+		typeName := metadataResp.TypeName
+
+		if schemaResp.Schema.Version == 0 {
+			continue
+		}
+		upgradable, ok := r.(resource.ResourceWithUpgradeState)
+		if !ok {
+			t.Errorf("%s does not implement state upgrades", typeName)
+			continue
+		}
+		upgraders := upgradable.UpgradeState(ctx)
+
+		for version := int64(0); version < schemaResp.Schema.Version; version++ {
+			t.Run(fmt.Sprintf("%s_v%d", typeName, version), func(t *testing.T) {
+				upgrader, ok := upgraders[version]
+				if !ok {
+					t.Fatalf("no state upgrader for version %d", version)
+				}
+
+				fixture, err := os.ReadFile(filepath.Join("testdata", "state_upgrade", fmt.Sprintf("%s_v%d.json", typeName, version)))
+				if err != nil {
+					t.Fatalf("missing state fixture: %s", err)
+				}
+				var prior map[string]interface{}
+				if err := json.Unmarshal(fixture, &prior); err != nil {
+					t.Fatalf("invalid state fixture: %s", err)
+				}
+
+				resp := &resource.UpgradeStateResponse{State: tfsdk.State{Schema: schemaResp.Schema}}
+				upgrader.StateUpgrader(ctx, resource.UpgradeStateRequest{
+					RawState: &tfprotov6.RawState{JSON: fixture},
+				}, resp)
+				if resp.Diagnostics.HasError() {
+					t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+				}
+
+				var id types.String
+				resp.Diagnostics.Append(resp.State.GetAttribute(ctx, path.Root("id"), &id)...)
+				var deletionProtection types.Bool
+				resp.Diagnostics.Append(resp.State.GetAttribute(ctx, path.Root("deletion_protection"), &deletionProtection)...)
+				if resp.Diagnostics.HasError() {
+					t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+				}
+
+				if id.ValueString() != prior["id"] {
+					t.Errorf("expected id %v to be kept, got %q", prior["id"], id.ValueString())
+				}
+				if deletionProtection.IsNull() || deletionProtection.ValueBool() {
+					t.Errorf("expected deletion_protection to default to false, got %s", deletionProtection)
+				}
+			})
+		}
+	}
+}
+
+func TestUpgradeState_KeepsValues(t *testing.T) {
+	ctx := context.Background()
+
+	fixture, err := os.ReadFile(filepath.Join("testdata", "state_upgrade", "zenml_stack_v0.json"))
+	if err != nil {
+		t.Fatalf("missing state fixture: %s", err)
+	}
+
+	schemaResp := &resource.SchemaResponse{}
+	(&StackResource{}).Schema(ctx, resource.SchemaRequest{}, schemaResp)
+	resp := &resource.UpgradeStateResponse{State: tfsdk.State{Schema: schemaResp.Schema}}
+	(&StackResource{}).UpgradeState(ctx)[0].StateUpgrader(ctx, resource.UpgradeStateRequest{
+		RawState: &tfprotov6.RawState{JSON: fixture},
+	}, resp)
+
+	var data StackResourceModel
+	resp.Diagnostics.Append(resp.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+	}
+
+	if data.Name.ValueString() != "production" {
+		t.Errorf("expected name production, got %q", data.Name.ValueString())
+	}
+	components := data.Components.Elements()
+	if len(components) != 2 || components["orchestrator"].(types.String).ValueString() != "7c2e4d3b-1f6a-4b8e-9d0c-5a4b3c2d1e22" {
+		t.Errorf("expected components to be kept, got %s", data.Components)
+	}
+	if !data.Environment.IsNull() || !data.Secrets.IsNull() {
+		t.Errorf("expected new attributes to be null, got %s and %s", data.Environment, data.Secrets)
+	}
+}
+
+func TestUpgradeState_DropsRemovedAttributes(t *testing.T) {
+	ctx := context.Background()
+
+	schemaResp := &resource.SchemaResponse{}
+	(&SecretResource{}).Schema(ctx, resource.SchemaRequest{}, schemaResp)
+	resp := &resource.UpgradeStateResponse{State: tfsdk.State{Schema: schemaResp.Schema}}
+	(&SecretResource{}).UpgradeState(ctx)[0].StateUpgrader(ctx, resource.UpgradeStateRequest{
+		RawState: &tfprotov6.RawState{JSON: []byte(`{"id": "secret-1", "name": "db", "workspace": "default"}`)},
+	}, resp)
+
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+	}
+}
@@ -3,10 +3,16 @@ package provider
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
 
-	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 )
 
@@ -107,8 +113,21 @@ var (
 		"orchestrator":   true,
 		"artifact_store": true,
 	}
+
+	uuidPattern = regexp.MustCompile(
+		`^[0-9a-fA-F]{8}-?[0-9a-fA-F]{4}-?[0-9a-fA-F]{4}-?[0-9a-fA-F]{4}-?[0-9a-fA-F]{12}$`,
+	)
 )
 
+// isUUID reports whether the given value looks like a UUID, as opposed to a
+// human-readable name.
+func isUUID(value string) bool {
+	return uuidPattern.MatchString(value)
+}
+
+// NormalizeServerConfig converts a configuration returned by the ZenML API to
+// strings. Values that are not strings are JSON-encoded and unset values are
+// left out.
 func NormalizeServerConfig(raw map[string]interface{}) map[string]string {
 	if raw == nil {
 		return map[string]string{}
@@ -116,74 +135,266 @@ func NormalizeServerConfig(raw map[string]interface{}) map[string]string {
 	normalized := make(map[string]string, len(raw))
 	for k, v := range raw {
 		switch vv := v.(type) {
+		case nil:
+			continue
 		case string:
 			normalized[k] = vv
 		default:
-			normalized[k] = fmt.Sprintf("%v", vv)
+			if encoded, err := json.Marshal(vv); err == nil {
+				normalized[k] = string(encoded)
+			} else {
+				normalized[k] = fmt.Sprintf("%v", vv)
+			}
 		}
 	}
 	return normalized
 }
 
-func MergeOrCompareConfiguration(
+// configurationRefresh selects how a configuration map in state is reconciled
+// with the configuration returned by the ZenML server.
+type configurationRefresh int
+
+const (
+	// configurationKeep keeps the configuration in state as is, which is
+	// required after create and update, where state must match the plan.
+	configurationKeep configurationRefresh = iota
+	// configurationTrack refreshes the keys in state from the server, so that
+	// changes made outside of Terraform show up in the plan. Keys that are not
+	// in state are not tracked.
+	configurationTrack
+	// configurationAdopt replaces the configuration in state with all keys of
+	// the server, for objects that Terraform has not managed before.
+	configurationAdopt
+)
+
+// ReconcileConfiguration returns the configuration to keep in state for the
+// given refresh mode. Only the keys configured by the user are tracked for
+// drift, and values that are semantically equal to the value in state, such
+// as the same JSON document with different formatting, keep their form in
+// state. Keys that are null or missing on the server are dropped, so that the
+// plan restores them. Only the given secret keys, which the server stores
+// elsewhere and never returns, keep their value in state.
+func ReconcileConfiguration(
 	ctx context.Context,
 	existing types.Map,
 	serverRaw map[string]interface{},
+	secretKeys map[string]bool,
+	mode configurationRefresh,
 	diags *diag.Diagnostics,
-) (types.Map, bool) {
-
-	// MergeOrCompareConfiguration centralizes the logic for reconciling a
-	// Terraform configuration (types.Map of strings) with a server-provided
-	// configuration (raw map). It returns a merged configuration with server
-	// values overlayed on top of the existing data configuration, and warns
-	// about any keys present only in data.
-	//
-	// This is required because the ZenML server may mutate the configuration
-	// of stack components and service connectors on create/update, and we need
-	// to ensure that the Terraform configuration provided by the user is never
-	// overwritten by the server.
-
+) types.Map {
 	serverConfig := NormalizeServerConfig(serverRaw)
 
-	existingTyped := make(map[string]types.String)
-	if !existing.IsNull() && !existing.IsUnknown() {
-		diags.Append(existing.ElementsAs(ctx, &existingTyped, false)...)
-		if diags.HasError() {
-			return types.Map{}, false
+	switch mode {
+	case configurationKeep:
+		return existing
+	case configurationAdopt:
+		if len(serverConfig) == 0 {
+			return types.MapNull(types.StringType)
 		}
+		cfg, cfgDiags := types.MapValueFrom(ctx, types.StringType, serverConfig)
+		diags.Append(cfgDiags...)
+		return cfg
 	}
 
-	// Merge: start with existing, overlay server.
-	merged := make(map[string]attr.Value, len(existingTyped)+len(serverConfig))
-	ignoredKeys := make([]string, 0)
+	if existing.IsNull() || existing.IsUnknown() {
+		return existing
+	}
 
+	existingTyped := make(map[string]string, len(existing.Elements()))
+	diags.Append(existing.ElementsAs(ctx, &existingTyped, false)...)
+	if diags.HasError() {
+		return existing
+	}
+
+	reconciled := make(map[string]string, len(existingTyped))
 	for k, v := range existingTyped {
-		if _, ok := serverConfig[k]; !ok {
+		serverValue, ok := serverConfig[k]
+		switch {
+		case ok && !configValuesEqual(v, serverValue):
+			reconciled[k] = serverValue
+		case ok, secretKeys[k]:
+			reconciled[k] = v
+		default:
+			// Cleared or removed outside of Terraform.
+		}
+	}
+
+	cfg, cfgDiags := types.MapValueFrom(ctx, types.StringType, reconciled)
+	diags.Append(cfgDiags...)
+	return cfg
+}
+
+// EffectiveConfiguration returns the complete configuration returned by the
+// ZenML server, including the defaults it fills in.
+func EffectiveConfiguration(ctx context.Context, serverRaw map[string]interface{}, diags *diag.Diagnostics) types.Map {
+	cfg, cfgDiags := types.MapValueFrom(ctx, types.StringType, NormalizeServerConfig(serverRaw))
+	diags.Append(cfgDiags...)
+	return cfg
+}
+
+// WarnIgnoredConfiguration warns about configured keys that the ZenML server
+// did not return.
+func WarnIgnoredConfiguration(configured types.Map, serverRaw map[string]interface{}, diags *diag.Diagnostics) {
+	if configured.IsNull() || configured.IsUnknown() {
+		return
+	}
+
+	ignoredKeys := make([]string, 0)
+	for k := range configured.Elements() {
+		if _, ok := serverRaw[k]; !ok {
 			ignoredKeys = append(ignoredKeys, k)
 		}
-		merged[k] = types.StringValue(v.ValueString())
 	}
+	if len(ignoredKeys) == 0 {
+		return
+	}
+
+	sort.Strings(ignoredKeys)
+	diags.AddWarning(
+		"Configuration attributes ignored by ZenML server",
+		fmt.Sprintf(
+			"The following configuration attributes are present in Terraform "+
+				"state but not recognized by the server and were ignored: %v.",
+			ignoredKeys,
+		),
+	)
+}
 
-	for k, v := range serverConfig {
-		merged[k] = types.StringValue(v)
+// configValuesEqual reports whether a configured value and a value returned
+// by the server are semantically equal.
+func configValuesEqual(configured, server string) bool {
+	if configured == server {
+		return true
 	}
 
-	cfg, cfgDiags := types.MapValue(types.StringType, merged)
-	diags.Append(cfgDiags...)
-	if diags.HasError() {
-		return types.Map{}, false
+	if b1, ok := parseConfigBool(configured); ok {
+		b2, ok := parseConfigBool(server)
+		return ok && b1 == b2
+	}
+
+	var v1, v2 interface{}
+	if json.Unmarshal([]byte(configured), &v1) != nil || json.Unmarshal([]byte(server), &v2) != nil {
+		return false
 	}
+	return reflect.DeepEqual(v1, v2)
+}
 
-	if len(ignoredKeys) > 0 {
-		diags.AddWarning(
-			"Configuration attributes ignored by ZenML server",
-			fmt.Sprintf(
-				"The following configuration attributes are present in Terraform "+
-					"state but not recognized by the server and were ignored: %v.",
-				ignoredKeys,
-			),
-		)
+// parseConfigBool parses the spellings of booleans used in Terraform and
+// Python configuration.
+func parseConfigBool(value string) (bool, bool) {
+	switch {
+	case strings.EqualFold(value, "true"):
+		return true, true
+	case strings.EqualFold(value, "false"):
+		return false, true
+	}
+	return false, false
+}
+
+// secretConfigurationValidator validates that no key is set in both
+// configuration and secret_configuration.
+type secretConfigurationValidator struct{}
+
+func (v secretConfigurationValidator) Description(ctx context.Context) string {
+	return "Validates that configuration and secret_configuration do not share keys"
+}
+
+func (v secretConfigurationValidator) MarkdownDescription(ctx context.Context) string {
+	return "Validates that `configuration` and `secret_configuration` do not share keys"
+}
+
+func (v secretConfigurationValidator) ValidateResource(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var configuration, secretConfiguration types.Map
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("configuration"), &configuration)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("secret_configuration"), &secretConfiguration)...)
+	if resp.Diagnostics.HasError() || configuration.IsUnknown() || secretConfiguration.IsUnknown() {
+		return
+	}
+
+	secretKeys := secretConfiguration.Elements()
+	duplicates := make([]string, 0)
+	for k := range configuration.Elements() {
+		if _, ok := secretKeys[k]; ok {
+			duplicates = append(duplicates, k)
+		}
+	}
+	if len(duplicates) == 0 {
+		return
+	}
+
+	sort.Strings(duplicates)
+	resp.Diagnostics.AddAttributeError(
+		path.Root("secret_configuration"),
+		"Duplicate Configuration Keys",
+		fmt.Sprintf(
+			"The keys %v are set in both configuration and secret_configuration. "+
+				"Set each key in only one of them.",
+			duplicates,
+		),
+	)
+}
+
+// credentialKeyFragments are parts of configuration key names that suggest the
+// value is a credential, such as aws_secret_access_key or api_token.
+var credentialKeyFragments = []string{
+	"secret", "password", "token", "credential", "api_key", "access_key", "private_key",
+}
+
+// looksLikeCredentialKey reports whether a configuration key name suggests
+// that its value is a credential.
+func looksLikeCredentialKey(key string) bool {
+	key = strings.ToLower(key)
+	for _, fragment := range credentialKeyFragments {
+		if strings.Contains(key, fragment) {
+			return true
+		}
+	}
+	return false
+}
+
+// credentialConfigurationValidator warns about keys of configuration that look
+// like credentials, since configuration is shown in plans. Values that refer
+// to a ZenML secret, such as {{aws.secret_key}}, are not credentials
+// themselves and are not reported.
+type credentialConfigurationValidator struct{}
+
+func (v credentialConfigurationValidator) Description(ctx context.Context) string {
+	return "Warns about credentials set in configuration instead of secret_configuration"
+}
+
+func (v credentialConfigurationValidator) MarkdownDescription(ctx context.Context) string {
+	return "Warns about credentials set in `configuration` instead of `secret_configuration`"
+}
+
+func (v credentialConfigurationValidator) ValidateResource(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var configuration types.Map
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("configuration"), &configuration)...)
+	if resp.Diagnostics.HasError() || configuration.IsNull() || configuration.IsUnknown() {
+		return
+	}
+
+	keys := make([]string, 0)
+	for k, value := range configuration.Elements() {
+		if s, ok := value.(types.String); ok && !s.IsUnknown() && isSecretReference(s.ValueString()) {
+			continue
+		}
+		if looksLikeCredentialKey(k) {
+			keys = append(keys, k)
+		}
+	}
+	if len(keys) == 0 {
+		return
 	}
 
-	return cfg, true
+	sort.Strings(keys)
+	resp.Diagnostics.AddAttributeWarning(
+		path.Root("configuration"),
+		"Possible Credentials in Configuration",
+		fmt.Sprintf(
+			"The keys %v of configuration look like credentials, whose values are shown in plans. "+
+				"Move them to secret_configuration, or refer to a ZenML secret with "+
+				"\"{{secret_name.key}}\" where the flavor supports it.",
+			keys,
+		),
+	)
 }
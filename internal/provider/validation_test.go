@@ -0,0 +1,205 @@
+package provider
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestReconcileConfiguration(t *testing.T) {
+	ctx := context.Background()
+
+	server := map[string]interface{}{
+		"path":        "gs://changed-in-dashboard",
+		"synchronous": true,
+		"settings":    map[string]interface{}{"retries": float64(3), "backoff": "linear"},
+		"timeout":     float64(0),
+		"namespace":   nil,
+	}
+
+	cases := []struct {
+		name     string
+		existing map[string]string
+		mode     configurationRefresh
+		want     map[string]string
+	}{
+		{
+			name:     "tracks managed keys",
+			existing: map[string]string{"path": "gs://bucket"},
+			mode:     configurationTrack,
+			want:     map[string]string{"path": "gs://changed-in-dashboard"},
+		},
+		{
+			name: "keeps semantically equal values",
+			existing: map[string]string{
+				"synchronous": "True",
+				"settings":    `{ "backoff": "linear", "retries": 3 }`,
+				"timeout":     "0",
+			},
+			mode: configurationTrack,
+			want: map[string]string{
+				"synchronous": "True",
+				"settings":    `{ "backoff": "linear", "retries": 3 }`,
+				"timeout":     "0",
+			},
+		},
+		{
+			name:     "drops keys cleared on the server",
+			existing: map[string]string{"namespace": "zenml", "timeout": "0"},
+			mode:     configurationTrack,
+			want:     map[string]string{"timeout": "0"},
+		},
+		{
+			name:     "drops keys removed on the server",
+			existing: map[string]string{"region": "us-east-1", "timeout": "0"},
+			mode:     configurationTrack,
+			want:     map[string]string{"timeout": "0"},
+		},
+		{
+			name:     "keeps secret keys the server does not return",
+			existing: map[string]string{"aws_secret_access_key": "secret"},
+			mode:     configurationTrack,
+			want:     map[string]string{"aws_secret_access_key": "secret"},
+		},
+		{
+			name:     "keeps planned values",
+			existing: map[string]string{"path": "gs://bucket"},
+			mode:     configurationKeep,
+			want:     map[string]string{"path": "gs://bucket"},
+		},
+		{
+			name: "adopts all server keys",
+			mode: configurationAdopt,
+			want: map[string]string{
+				"path":        "gs://changed-in-dashboard",
+				"synchronous": "true",
+				"settings":    `{"backoff":"linear","retries":3}`,
+				"timeout":     "0",
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var diags diag.Diagnostics
+			existing := types.MapNull(types.StringType)
+			if tc.existing != nil {
+				existing, diags = types.MapValueFrom(ctx, types.StringType, tc.existing)
+			}
+
+			got := ReconcileConfiguration(ctx, existing, server, connectorSecretKeys, tc.mode, &diags)
+			if diags.HasError() {
+				t.Fatalf("unexpected diagnostics: %v", diags)
+			}
+
+			var gotMap map[string]string
+			diags.Append(got.ElementsAs(ctx, &gotMap, false)...)
+			if diags.HasError() {
+				t.Fatalf("unexpected diagnostics: %v", diags)
+			}
+			if len(gotMap) != len(tc.want) {
+				t.Fatalf("expected %v, got %v", tc.want, gotMap)
+			}
+			for k, v := range tc.want {
+				if gotMap[k] != v {
+					t.Errorf("expected %s = %q, got %q", k, v, gotMap[k])
+				}
+			}
+		})
+	}
+}
+
+func TestReconcileConfiguration_IgnoresUnmanagedKeys(t *testing.T) {
+	ctx := context.Background()
+	var diags diag.Diagnostics
+
+	got := ReconcileConfiguration(ctx, types.MapNull(types.StringType), map[string]interface{}{"path": "/tmp"}, nil, configurationTrack, &diags)
+	if diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+	if !got.IsNull() {
+		t.Errorf("expected an unset configuration to stay unset, got %s", got)
+	}
+}
+
+func TestSecretConfigurationValidator(t *testing.T) {
+	ctx := context.Background()
+
+	plan, _ := testStackComponentPlanValue(t, "s3", false)
+	diags := plan.SetAttribute(ctx, path.Root("configuration"), map[string]string{"path": "s3://bucket", "key": "id"})
+	diags.Append(plan.SetAttribute(ctx, path.Root("secret_configuration"), map[string]string{"key": "id", "secret": "value"})...)
+	if diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+	config := tfsdk.Config{Schema: plan.Schema, Raw: plan.Raw}
+
+	resp := &resource.ValidateConfigResponse{}
+	secretConfigurationValidator{}.ValidateResource(ctx, resource.ValidateConfigRequest{Config: config}, resp)
+	if !resp.Diagnostics.HasError() {
+		t.Fatalf("expected an error for a key set in both maps")
+	}
+	if detail := resp.Diagnostics.Errors()[0].Detail(); !strings.Contains(detail, "[key]") {
+		t.Errorf("expected the duplicate key to be reported, got %q", detail)
+	}
+
+	diags = plan.SetAttribute(ctx, path.Root("configuration"), map[string]string{"path": "s3://bucket"})
+	if diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+	config.Raw = plan.Raw
+	resp = &resource.ValidateConfigResponse{}
+	secretConfigurationValidator{}.ValidateResource(ctx, resource.ValidateConfigRequest{Config: config}, resp)
+	if resp.Diagnostics.HasError() {
+		t.Errorf("unexpected diagnostics: %v", resp.Diagnostics)
+	}
+}
+
+func TestCredentialConfigurationValidator(t *testing.T) {
+	ctx := context.Background()
+
+	plan, _ := testStackComponentPlanValue(t, "s3", false)
+	diags := plan.SetAttribute(ctx, path.Root("configuration"), map[string]string{
+		"path":                  "s3://bucket",
+		"aws_secret_access_key": "value",
+		"api_token":             "{{mlflow.token}}",
+	})
+	if diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+	config := tfsdk.Config{Schema: plan.Schema, Raw: plan.Raw}
+
+	resp := &resource.ValidateConfigResponse{}
+	credentialConfigurationValidator{}.ValidateResource(ctx, resource.ValidateConfigRequest{Config: config}, resp)
+	warnings := resp.Diagnostics.Warnings()
+	if resp.Diagnostics.HasError() || len(warnings) != 1 ||
+		!strings.Contains(warnings[0].Detail(), "[aws_secret_access_key]") {
+		t.Errorf("expected a warning for the credential only, got %v", resp.Diagnostics)
+	}
+}
+
+func TestStackComponentAPIConfiguration_MergesSecrets(t *testing.T) {
+	ctx := context.Background()
+	var diags diag.Diagnostics
+
+	configuration, d := types.MapValueFrom(ctx, types.StringType, map[string]string{"path": "s3://bucket"})
+	diags.Append(d...)
+	secretConfiguration, d := types.MapValueFrom(ctx, types.StringType, map[string]string{"secret": "value"})
+	diags.Append(d...)
+
+	got := (&StackComponentResource{}).apiConfiguration(ctx, &StackComponentResourceModel{
+		Configuration:       configuration,
+		SecretConfiguration: secretConfiguration,
+	}, &diags)
+	if diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+	if len(got) != 2 || got["path"] != "s3://bucket" || got["secret"] != "value" {
+		t.Errorf("expected both maps to be sent, got %v", got)
+	}
+}
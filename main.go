@@ -4,7 +4,9 @@ package main
 import (
 	"context"
 	"flag"
+	"fmt"
 	"log"
+	"os"
 
 	"terraform-provider-zenml/internal/provider"
 
@@ -21,6 +23,13 @@ var (
 )
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "export" {
+		if err := runExport(os.Args[2:]); err != nil {
+			log.Fatal(err.Error())
+		}
+		return
+	}
+
 	var debug bool
 
 	flag.BoolVar(&debug, "debug", false, "set to true to run the provider with support for debuggers like delve")
@@ -37,3 +46,47 @@ func main() {
 		log.Fatal(err.Error())
 	}
 }
+
+// runExport writes Terraform configuration with import blocks for all objects
+// on a ZenML server. The connection settings default to the same environment
+// variables as the provider configuration.
+func runExport(args []string) error {
+	flags := flag.NewFlagSet("export", flag.ExitOnError)
+	serverURL := flags.String("server-url", os.Getenv("ZENML_SERVER_URL"), "URL of the ZenML server")
+	apiKey := flags.String("api-key", os.Getenv("ZENML_API_KEY"), "ZenML API key")
+	apiToken := flags.String("api-token", os.Getenv("ZENML_API_TOKEN"), "ZenML API token")
+	output := flags.String("output", "", "file to write the configuration to (default: stdout)")
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: %s export [flags]\n\n", os.Args[0])
+		fmt.Fprintln(flags.Output(), "Writes Terraform configuration and import blocks for the objects on a ZenML server.")
+		fmt.Fprintln(flags.Output())
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if *serverURL == "" {
+		return fmt.Errorf("a server URL is required, set -server-url or ZENML_SERVER_URL")
+	}
+	if *apiKey == "" && *apiToken == "" {
+		return fmt.Errorf("an API key or token is required, set -api-key, -api-token, ZENML_API_KEY or ZENML_API_TOKEN")
+	}
+
+	client := provider.NewClient(*serverURL, *apiKey, *apiToken)
+	if *output == "" {
+		return provider.Export(context.Background(), client, os.Stdout)
+	}
+
+	f, err := os.Create(*output)
+	if err != nil {
+		return err
+	}
+	if err := provider.Export(context.Background(), client, f); err != nil {
+		f.Close()
+		return err
+	}
+	// Write errors can surface only on close, so a failed close means the
+	// file is incomplete.
+	return f.Close()
+}
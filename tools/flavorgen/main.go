@@ -0,0 +1,140 @@
+// tools/flavorgen/main.go
+
+// Command flavorgen generates the typed stack component resources of the
+// provider from a snapshot of ZenML flavor configuration schemas. With
+// -update, it first refreshes the snapshot from a ZenML server.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"terraform-provider-zenml/internal/flavorgen"
+	"terraform-provider-zenml/internal/provider"
+)
+
+func main() {
+	snapshot := flag.String("snapshot", "flavor_schemas.json", "snapshot of flavor configuration schemas")
+	output := flag.String("output", "", "file to write the generated Go code to")
+	docs := flag.String("docs", "", "directory to write the generated resource documentation to")
+	update := flag.Bool("update", false, "refresh the snapshot from the server set by ZENML_SERVER_URL before generating")
+	add := flag.String("add", "", "comma-separated <type>/<flavor> pairs to add to the snapshot with -update")
+	flag.Parse()
+
+	if *update {
+		if err := updateSnapshot(context.Background(), *snapshot, *add); err != nil {
+			log.Fatal(err.Error())
+		}
+	}
+
+	if err := generate(*snapshot, *output, *docs); err != nil {
+		log.Fatal(err.Error())
+	}
+}
+
+func generate(snapshot, output, docs string) error {
+	data, err := os.ReadFile(snapshot)
+	if err != nil {
+		return err
+	}
+
+	flavors, err := flavorgen.ParseSnapshot(data)
+	if err != nil {
+		return err
+	}
+
+	components, err := flavorgen.Components(flavors)
+	if err != nil {
+		return err
+	}
+
+	if output != "" {
+		code, err := flavorgen.GenerateGo(components, filepath.Base(snapshot))
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(output, code, 0o644); err != nil {
+			return err
+		}
+	}
+
+	if docs != "" {
+		pages, err := flavorgen.GenerateDocs(components)
+		if err != nil {
+			return err
+		}
+		for name, page := range pages {
+			if err := os.WriteFile(filepath.Join(docs, name), page, 0o644); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// updateSnapshot replaces the flavors in the snapshot with their current
+// schemas on the server. Only the flavors already in the snapshot and the
+// added ones are kept, so that the provider does not grow a resource for
+// every flavor of every integration.
+func updateSnapshot(ctx context.Context, snapshot, add string) error {
+	serverURL := os.Getenv("ZENML_SERVER_URL")
+	apiKey := os.Getenv("ZENML_API_KEY")
+	apiToken := os.Getenv("ZENML_API_TOKEN")
+	if serverURL == "" {
+		return fmt.Errorf("a server URL is required, set ZENML_SERVER_URL")
+	}
+	if apiKey == "" && apiToken == "" {
+		return fmt.Errorf("an API key or token is required, set ZENML_API_KEY or ZENML_API_TOKEN")
+	}
+
+	wanted := make(map[string]bool)
+	if data, err := os.ReadFile(snapshot); err == nil {
+		flavors, err := flavorgen.ParseSnapshot(data)
+		if err != nil {
+			return err
+		}
+		for _, f := range flavors {
+			if f.Body != nil {
+				wanted[f.Body.Type+"/"+f.Name] = true
+			}
+		}
+	} else if !os.IsNotExist(err) {
+		return err
+	}
+	for _, pair := range strings.Split(add, ",") {
+		if pair = strings.TrimSpace(pair); pair != "" {
+			wanted[pair] = true
+		}
+	}
+
+	client := provider.NewClient(serverURL, apiKey, apiToken)
+	flavors, err := client.ListAllFlavors(ctx)
+	if err != nil {
+		return fmt.Errorf("unable to list flavors: %w", err)
+	}
+
+	selected := make([]provider.FlavorResponse, 0, len(wanted))
+	for _, f := range flavors {
+		if f.Body == nil || !wanted[f.Body.Type+"/"+f.Name] {
+			continue
+		}
+		delete(wanted, f.Body.Type+"/"+f.Name)
+		selected = append(selected, f)
+	}
+	for pair := range wanted {
+		return fmt.Errorf("flavor %s is not available on the server", pair)
+	}
+
+	data, err := json.MarshalIndent(selected, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(snapshot, append(data, '\n'), 0o644)
+}